@@ -0,0 +1,195 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+//go:embed addons/ingress-nginx.yaml
+var ingressNginxManifest string
+
+//go:embed addons/metallb.yaml
+var metallbManifest string
+
+// metallbPoolManifest announces the generated address pool over L2.
+const metallbPoolManifest = `apiVersion: metallb.io/v1beta1
+kind: IPAddressPool
+metadata:
+  name: kind-pool
+  namespace: metallb-system
+spec:
+  addresses:
+  - %s
+---
+apiVersion: metallb.io/v1beta1
+kind: L2Advertisement
+metadata:
+  name: kind-pool
+  namespace: metallb-system
+spec:
+  ipAddressPools:
+  - kind-pool
+`
+
+// ingressReadyPatch labels the node that runs the ingress controller.
+const ingressReadyPatch = `kind: InitConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+    node-labels: "ingress-ready=true"`
+
+// addonRolloutTimeout bounds how long an addon may take to become available.
+const addonRolloutTimeout = "5m"
+
+// firstControlPlaneNode returns the first control-plane node of a generated
+// Kind config, adding a default control-plane node when no nodes are
+// configured.
+func firstControlPlaneNode(config map[string]interface{}) map[string]interface{} {
+	nodes, _ := config["nodes"].([]map[string]interface{})
+	for _, node := range nodes {
+		if node["role"] == "control-plane" {
+			return node
+		}
+	}
+
+	node := map[string]interface{}{"role": "control-plane"}
+	config["nodes"] = append([]map[string]interface{}{node}, nodes...)
+	return node
+}
+
+// appendNodePortMapping adds an extra port mapping to a generated node.
+func appendNodePortMapping(node map[string]interface{}, containerPort, hostPort int) {
+	ports, _ := node["extraPortMappings"].([]map[string]interface{})
+	node["extraPortMappings"] = append(ports, map[string]interface{}{
+		"containerPort": containerPort,
+		"hostPort":      hostPort,
+		"protocol":      "TCP",
+	})
+}
+
+// appendNodeKubeadmPatch adds a kubeadm config patch to a generated node.
+func appendNodeKubeadmPatch(node map[string]interface{}, patch string) {
+	patches, _ := node["kubeadmConfigPatches"].([]string)
+	node["kubeadmConfigPatches"] = append(patches, patch)
+}
+
+// getBlock returns the single nested block stored under key, or nil when the
+// block is not configured.
+func getBlock(d *schema.ResourceData, key string) map[string]interface{} {
+	blocks, _ := d.Get(key).([]interface{})
+	if len(blocks) == 0 {
+		return nil
+	}
+	// Blocks without any attributes set are read as nil
+	if blocks[0] == nil {
+		return map[string]interface{}{}
+	}
+	return blocks[0].(map[string]interface{})
+}
+
+// configureIngress labels the first control-plane node as ingress-ready and
+// maps the controller's ports to the host.
+func configureIngress(d *schema.ResourceData, config map[string]interface{}) {
+	ingress := getBlock(d, "ingress")
+	if ingress == nil {
+		return
+	}
+
+	node := firstControlPlaneNode(config)
+	appendNodeKubeadmPatch(node, ingressReadyPatch)
+	appendNodePortMapping(node, 80, ingress["http_port"].(int))
+	appendNodePortMapping(node, 443, ingress["https_port"].(int))
+}
+
+// installIngress installs the configured ingress controller and waits for it
+// to roll out.
+func installIngress(d *schema.ResourceData, config *ProviderConfig, clusterName string) diag.Diagnostics {
+	ingress := getBlock(d, "ingress")
+	if ingress == nil {
+		return nil
+	}
+
+	log.Printf("[INFO] Installing %s ingress controller into Kind cluster %s", ingress["controller"], clusterName)
+
+	return installAddon(config, clusterName, ingressNginxManifest, "ingress-nginx", "deployment/ingress-nginx-controller")
+}
+
+// installLoadBalancer installs MetalLB with an address pool carved from the
+// cluster's Docker network, unless an address range is configured.
+func installLoadBalancer(d *schema.ResourceData, config *ProviderConfig, clusterName string) diag.Diagnostics {
+	loadBalancer := getBlock(d, "load_balancer")
+	if loadBalancer == nil {
+		return nil
+	}
+
+	addressRange := loadBalancer["address_range"].(string)
+	if addressRange == "" {
+		network := clusterNetwork(d)
+		subnets, err := getNetworkSubnets(config, network)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		subnet, err := firstIPv4Subnet(subnets)
+		if err != nil {
+			return diag.Errorf("Failed to find the subnet of network %s: %s", network, err)
+		}
+		addressRange, err = carveAddressPool(subnet)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[INFO] Installing MetalLB into Kind cluster %s with address pool %s", clusterName, addressRange)
+
+	if diags := installAddon(config, clusterName, metallbManifest, "metallb-system", "deployment/controller", "daemonset/speaker"); diags.HasError() {
+		return diags
+	}
+	if diags := installAddon(config, clusterName, fmt.Sprintf(metallbPoolManifest, addressRange), "metallb-system"); diags.HasError() {
+		return diags
+	}
+
+	loadBalancer["address_range"] = addressRange
+	d.Set("load_balancer", []interface{}{loadBalancer})
+	return nil
+}
+
+// installAddon applies an embedded addon manifest and waits for the rollout
+// of the given workloads.
+func installAddon(config *ProviderConfig, clusterName, manifest, namespace string, workloads ...string) diag.Diagnostics {
+	documents, err := parseManifest(manifest)
+	if err != nil {
+		return diag.Errorf("Failed to parse addon manifest: %s", err)
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	if diags := applyManifestDocuments(kubeconfigPath, documents); diags.HasError() {
+		return diags
+	}
+
+	for _, workload := range workloads {
+		if err := waitForRollout(kubeconfigPath, namespace, workload); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// waitForRollout waits until a deployment or daemonset finished rolling out.
+func waitForRollout(kubeconfigPath, namespace, workload string) error {
+	cmd := kubectlCommand(kubeconfigPath, "rollout", "status", workload,
+		"--namespace", namespace, "--timeout", addonRolloutTimeout)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s in namespace %s did not roll out: %s\nOutput: %s", workload, namespace, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigureIngress_defaultNode tests that a control-plane node is added for the ingress controller
+func TestConfigureIngress_defaultNode(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"ingress": []interface{}{
+			map[string]interface{}{
+				"http_port":  8080,
+				"https_port": 8443,
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"role":                 "control-plane",
+			"kubeadmConfigPatches": []string{ingressReadyPatch},
+			"extraPortMappings": []map[string]interface{}{
+				{"containerPort": 80, "hostPort": 8080, "protocol": "TCP"},
+				{"containerPort": 443, "hostPort": 8443, "protocol": "TCP"},
+			},
+		},
+	}, config["nodes"])
+}
+
+// TestConfigureIngress_existingNodes tests that the ingress settings extend the first control-plane node
+func TestConfigureIngress_existingNodes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"ingress": []interface{}{
+			map[string]interface{}{},
+		},
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"node": []interface{}{
+					map[string]interface{}{
+						"role": "worker",
+					},
+					map[string]interface{}{
+						"role":                   "control-plane",
+						"kubeadm_config_patches": []interface{}{"kind: ClusterConfiguration"},
+						"extra_port_mappings": []interface{}{
+							map[string]interface{}{
+								"container_port": 30000,
+								"host_port":      30000,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+	nodes := config["nodes"].([]map[string]interface{})
+	require.Len(t, nodes, 2)
+
+	assert.Nil(t, nodes[0]["kubeadmConfigPatches"])
+	assert.Equal(t, []string{"kind: ClusterConfiguration", ingressReadyPatch}, nodes[1]["kubeadmConfigPatches"])
+	assert.Equal(t, []map[string]interface{}{
+		{"containerPort": 30000, "hostPort": 30000, "protocol": "TCP"},
+		{"containerPort": 80, "hostPort": 80, "protocol": "TCP"},
+		{"containerPort": 443, "hostPort": 443, "protocol": "TCP"},
+	}, nodes[1]["extraPortMappings"])
+}
+
+// TestIngressNginxManifest tests that the embedded ingress-nginx manifest parses
+func TestIngressNginxManifest(t *testing.T) {
+	documents, err := parseManifest(ingressNginxManifest)
+	require.NoError(t, err)
+
+	var objects []string
+	for _, document := range documents {
+		objects = append(objects, document.Object.String())
+	}
+	assert.Contains(t, objects, "Namespace ingress-nginx")
+	assert.Contains(t, objects, "Deployment ingress-nginx/ingress-nginx-controller")
+	assert.Contains(t, objects, "IngressClass nginx")
+}
+
+// TestGetBlock_empty tests that an empty load_balancer block enables the addon
+func TestGetBlock_empty(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name":          "test",
+		"load_balancer": []interface{}{map[string]interface{}{}},
+	})
+	assert.NotNil(t, getBlock(d, "load_balancer"))
+
+	d = schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+	})
+	assert.Nil(t, getBlock(d, "load_balancer"))
+}
+
+// TestMetallbManifest tests that the embedded MetalLB manifest and pool configuration parse
+func TestMetallbManifest(t *testing.T) {
+	documents, err := parseManifest(metallbManifest)
+	require.NoError(t, err)
+
+	var objects []string
+	for _, document := range documents {
+		objects = append(objects, document.Object.String())
+	}
+	assert.Contains(t, objects, "CustomResourceDefinition ipaddresspools.metallb.io")
+	assert.Contains(t, objects, "Deployment metallb-system/controller")
+	assert.Contains(t, objects, "DaemonSet metallb-system/speaker")
+
+	pool, err := parseManifest(fmt.Sprintf(metallbPoolManifest, "172.18.255.200-172.18.255.250"))
+	require.NoError(t, err)
+	require.Len(t, pool, 2)
+	assert.Contains(t, pool[0].YAML, "- 172.18.255.200-172.18.255.250")
+}
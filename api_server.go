@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// oidcCAPath is the directory the OIDC CA certificate is mounted to on the
+// control-plane nodes and in the API server.
+const oidcCAPath = "/etc/kubernetes/oidc"
+
+// apiServerConfig collects the API server settings generated from the
+// kind_cluster blocks. Kind applies kubeadm patches as merge patches, which
+// replace lists instead of merging them, so all settings are rendered into a
+// single ClusterConfiguration patch.
+type apiServerConfig struct {
+	ExtraArgs    map[string]string `yaml:"extraArgs,omitempty"`
+	ExtraVolumes []apiServerVolume `yaml:"extraVolumes,omitempty"`
+	CertSANs     []string          `yaml:"certSANs,omitempty"`
+}
+
+// apiServerVolume is a host path of the node mounted into the API server.
+type apiServerVolume struct {
+	Name      string `yaml:"name"`
+	HostPath  string `yaml:"hostPath"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly"`
+	PathType  string `yaml:"pathType"`
+}
+
+// componentConfig holds the settings of the other control plane components.
+type componentConfig struct {
+	ExtraArgs map[string]string `yaml:"extraArgs,omitempty"`
+}
+
+// clusterConfigurationPatch is the generated kubeadm ClusterConfiguration
+// patch. Components without settings are left out.
+type clusterConfigurationPatch struct {
+	Kind              string           `yaml:"kind"`
+	APIServer         *apiServerConfig `yaml:"apiServer,omitempty"`
+	ControllerManager *componentConfig `yaml:"controllerManager,omitempty"`
+	Scheduler         *componentConfig `yaml:"scheduler,omitempty"`
+	Etcd              *struct {
+		Local *componentConfig `yaml:"local"`
+	} `yaml:"etcd,omitempty"`
+}
+
+// componentExtraArgs returns the extra args configured in a map attribute,
+// or nil when there are none.
+func componentExtraArgs(d *schema.ResourceData, key string) *componentConfig {
+	args := expandStringMap(d.Get(key).(map[string]interface{}))
+	if len(args) == 0 {
+		return nil
+	}
+	return &componentConfig{ExtraArgs: args}
+}
+
+func expandStringMap(m map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+	return result
+}
+
+// controlPlaneNodes returns all control-plane nodes of a generated Kind
+// config, adding a default control-plane node when no nodes are configured.
+func controlPlaneNodes(config map[string]interface{}) []map[string]interface{} {
+	firstControlPlaneNode(config)
+
+	var controlPlanes []map[string]interface{}
+	for _, node := range config["nodes"].([]map[string]interface{}) {
+		if node["role"] == "control-plane" {
+			controlPlanes = append(controlPlanes, node)
+		}
+	}
+	return controlPlanes
+}
+
+// appendNodeExtraMount adds an extra mount to a generated node.
+func appendNodeExtraMount(node map[string]interface{}, hostPath, containerPath string, readOnly bool) {
+	if abs, err := filepath.Abs(hostPath); err == nil {
+		hostPath = abs
+	}
+	mounts, _ := node["extraMounts"].([]map[string]interface{})
+	node["extraMounts"] = append(mounts, map[string]interface{}{
+		"hostPath":      hostPath,
+		"containerPath": containerPath,
+		"readOnly":      readOnly,
+	})
+}
+
+// configureControlPlane renders the generated control plane settings into a
+// cluster wide kubeadm patch.
+func configureControlPlane(d *schema.ResourceData, config map[string]interface{}) error {
+	apiServer := &apiServerConfig{ExtraArgs: map[string]string{}}
+	configureOIDC(d, config, apiServer)
+	if err := configureAudit(d, config, apiServer); err != nil {
+		return fmt.Errorf("failed to configure audit logging: %s", err)
+	}
+	if err := configureEncryption(d, config, apiServer); err != nil {
+		return fmt.Errorf("failed to configure encryption at rest: %s", err)
+	}
+	configureExternalAccess(d, config, apiServer)
+
+	// Explicit flags take precedence over generated ones
+	for flag, value := range expandStringMap(d.Get("api_server_extra_args").(map[string]interface{})) {
+		apiServer.ExtraArgs[flag] = value
+	}
+	sort.Slice(apiServer.ExtraVolumes, func(i, j int) bool {
+		return apiServer.ExtraVolumes[i].Name < apiServer.ExtraVolumes[j].Name
+	})
+
+	patch := clusterConfigurationPatch{
+		Kind:              "ClusterConfiguration",
+		ControllerManager: componentExtraArgs(d, "controller_manager_extra_args"),
+		Scheduler:         componentExtraArgs(d, "scheduler_extra_args"),
+	}
+	if len(apiServer.ExtraArgs) > 0 || len(apiServer.ExtraVolumes) > 0 || len(apiServer.CertSANs) > 0 {
+		patch.APIServer = apiServer
+	}
+	if etcd := componentExtraArgs(d, "etcd_extra_args"); etcd != nil {
+		patch.Etcd = &struct {
+			Local *componentConfig `yaml:"local"`
+		}{etcd}
+	}
+	if patch.APIServer == nil && patch.ControllerManager == nil && patch.Scheduler == nil && patch.Etcd == nil {
+		return nil
+	}
+
+	data, _ := yaml.Marshal(patch)
+	patches, _ := config["kubeadmConfigPatches"].([]string)
+	config["kubeadmConfigPatches"] = append(patches, string(data))
+	return nil
+}
+
+// configureOIDC adds the OIDC flags of the API server and mounts the issuer
+// CA into the control-plane nodes.
+func configureOIDC(d *schema.ResourceData, config map[string]interface{}, apiServer *apiServerConfig) {
+	oidc := getBlock(d, "oidc")
+	if oidc == nil {
+		return
+	}
+
+	apiServer.ExtraArgs["oidc-issuer-url"] = oidc["issuer_url"].(string)
+	apiServer.ExtraArgs["oidc-client-id"] = oidc["client_id"].(string)
+	for attribute, flag := range map[string]string{
+		"username_claim":  "oidc-username-claim",
+		"username_prefix": "oidc-username-prefix",
+		"groups_claim":    "oidc-groups-claim",
+		"groups_prefix":   "oidc-groups-prefix",
+	} {
+		if value := oidc[attribute].(string); value != "" {
+			apiServer.ExtraArgs[flag] = value
+		}
+	}
+
+	caFile := oidc["ca_file"].(string)
+	if caFile == "" {
+		return
+	}
+	for _, node := range controlPlaneNodes(config) {
+		appendNodeExtraMount(node, caFile, oidcCAPath+"/ca.crt", true)
+	}
+	apiServer.ExtraArgs["oidc-ca-file"] = oidcCAPath + "/ca.crt"
+	apiServer.ExtraVolumes = append(apiServer.ExtraVolumes, apiServerVolume{
+		Name:      "oidc-ca",
+		HostPath:  oidcCAPath,
+		MountPath: oidcCAPath,
+		ReadOnly:  true,
+		PathType:  "DirectoryOrCreate",
+	})
+}
+
+// configureExternalAccess adds the certificate SANs, including the host of a
+// remote daemon, and makes the API server listen on all addresses only when
+// an external host is configured explicitly.
+func configureExternalAccess(d *schema.ResourceData, config map[string]interface{}, apiServer *apiServerConfig) {
+	sans := expandStringList(d.Get("api_server_cert_sans").([]interface{}))
+	if remoteHost := d.Get("api_server_remote_host").(string); remoteHost != "" {
+		sans = append(sans, remoteHost)
+	}
+	if externalHost := d.Get("api_server_external_host").(string); externalHost != "" {
+		sans = append(sans, externalHost)
+
+		networking, _ := config["networking"].(map[string]interface{})
+		if networking == nil {
+			networking = map[string]interface{}{}
+		}
+		if _, ok := networking["apiServerAddress"]; !ok {
+			networking["apiServerAddress"] = "0.0.0.0"
+		}
+		config["networking"] = networking
+	}
+
+	for _, san := range sans {
+		if !containsString(apiServer.CertSANs, san) {
+			apiServer.CertSANs = append(apiServer.CertSANs, san)
+		}
+	}
+}
+
+// rewriteServerHost replaces the host of an API server URL, keeping the
+// scheme and port. An empty host leaves the URL unchanged.
+func rewriteServerHost(server, host string) string {
+	if host == "" {
+		return server
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return server
+	}
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else {
+		u.Host = host
+	}
+	return u.String()
+}
+
+// rewriteKubeconfigServer points the server of a kubeconfig to another host.
+func rewriteKubeconfigServer(kubeconfig, server, host string) string {
+	if host == "" || server == "" {
+		return kubeconfig
+	}
+	return replaceKubeconfigServer(kubeconfig, server, rewriteServerHost(server, host))
+}
+
+// replaceKubeconfigServer replaces a server URL of a kubeconfig.
+func replaceKubeconfigServer(kubeconfig, server, replacement string) string {
+	return strings.ReplaceAll(kubeconfig, "server: "+server, "server: "+replacement)
+}
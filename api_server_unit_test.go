@@ -0,0 +1,219 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigureControlPlane_notConfigured tests that no patch is generated without control plane settings
+func TestConfigureControlPlane_notConfigured(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.NotContains(t, config, "kubeadmConfigPatches")
+	assert.NotContains(t, config, "nodes")
+}
+
+// TestConfigureOIDC tests the generated OIDC flags and CA mounts
+func TestConfigureOIDC(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"oidc": []interface{}{
+			map[string]interface{}{
+				"issuer_url":     "https://dex.example.com",
+				"client_id":      "kubernetes",
+				"username_claim": "email",
+				"groups_claim":   "groups",
+				"ca_file":        "/etc/dex/ca.crt",
+			},
+		},
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"node": []interface{}{
+					map[string]interface{}{"role": "control-plane"},
+					map[string]interface{}{"role": "control-plane"},
+					map[string]interface{}{"role": "worker"},
+				},
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []string{`kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    oidc-ca-file: /etc/kubernetes/oidc/ca.crt
+    oidc-client-id: kubernetes
+    oidc-groups-claim: groups
+    oidc-issuer-url: https://dex.example.com
+    oidc-username-claim: email
+  extraVolumes:
+  - name: oidc-ca
+    hostPath: /etc/kubernetes/oidc
+    mountPath: /etc/kubernetes/oidc
+    readOnly: true
+    pathType: DirectoryOrCreate
+`}, config["kubeadmConfigPatches"])
+
+	nodes := config["nodes"].([]map[string]interface{})
+	require.Len(t, nodes, 3)
+	for _, node := range nodes[:2] {
+		assert.Contains(t, node["extraMounts"], map[string]interface{}{
+			"hostPath":      "/etc/dex/ca.crt",
+			"containerPath": "/etc/kubernetes/oidc/ca.crt",
+			"readOnly":      true,
+		})
+	}
+	assert.Nil(t, nodes[2]["extraMounts"])
+}
+
+// TestConfigureOIDC_withoutCA tests that issuers with public certificates need no mounts
+func TestConfigureOIDC_withoutCA(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"oidc": []interface{}{
+			map[string]interface{}{
+				"issuer_url": "https://accounts.google.com",
+				"client_id":  "kubernetes",
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []string{`kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    oidc-client-id: kubernetes
+    oidc-issuer-url: https://accounts.google.com
+`}, config["kubeadmConfigPatches"])
+	assert.NotContains(t, config, "nodes")
+}
+
+// TestOIDCSchema tests that only https issuers are accepted
+func TestOIDCSchema(t *testing.T) {
+	validate := resourceKindCluster().Schema["oidc"].Elem.(*schema.Resource).Schema["issuer_url"].ValidateFunc
+
+	_, errs := validate("https://dex.example.com", "issuer_url")
+	assert.Empty(t, errs)
+	_, errs = validate("http://dex.example.com", "issuer_url")
+	assert.NotEmpty(t, errs)
+}
+
+// TestConfigureControlPlane_extraArgs tests rendering component flags with explicit flags taking precedence
+func TestConfigureControlPlane_extraArgs(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"oidc": []interface{}{
+			map[string]interface{}{
+				"issuer_url": "https://dex.example.com",
+				"client_id":  "kubernetes",
+			},
+		},
+		"api_server_extra_args": map[string]interface{}{
+			"oidc-client-id": "kind",
+			"v":              "4",
+		},
+		"controller_manager_extra_args": map[string]interface{}{
+			"bind-address": "0.0.0.0",
+		},
+		"scheduler_extra_args": map[string]interface{}{
+			"bind-address": "0.0.0.0",
+		},
+		"etcd_extra_args": map[string]interface{}{
+			"listen-metrics-urls": "http://0.0.0.0:2381",
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []string{`kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    oidc-client-id: kind
+    oidc-issuer-url: https://dex.example.com
+    v: "4"
+controllerManager:
+  extraArgs:
+    bind-address: 0.0.0.0
+scheduler:
+  extraArgs:
+    bind-address: 0.0.0.0
+etcd:
+  local:
+    extraArgs:
+      listen-metrics-urls: http://0.0.0.0:2381
+`}, config["kubeadmConfigPatches"])
+}
+
+// TestConfigureExternalAccess tests certificate SANs and the API server address for remote access
+func TestConfigureExternalAccess(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name":                     "test",
+		"api_server_cert_sans":     []interface{}{"kind.example.com", "docker-host.example.com"},
+		"api_server_external_host": "docker-host.example.com",
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"networking": []interface{}{
+					map[string]interface{}{"api_server_port": 6443},
+				},
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []string{`kind: ClusterConfiguration
+apiServer:
+  certSANs:
+  - kind.example.com
+  - docker-host.example.com
+`}, config["kubeadmConfigPatches"])
+	assert.Equal(t, map[string]interface{}{
+		"apiServerAddress": "0.0.0.0",
+		"apiServerPort":    6443,
+	}, config["networking"])
+}
+
+// TestConfigureExternalAccess_address tests that a configured API server address is kept
+func TestConfigureExternalAccess_address(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name":                     "test",
+		"api_server_external_host": "192.168.1.20",
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"networking": []interface{}{
+					map[string]interface{}{"api_server_address": "192.168.1.20"},
+				},
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, map[string]interface{}{"apiServerAddress": "192.168.1.20"}, config["networking"])
+}
+
+// TestRewriteServerHost tests replacing the host of the API server endpoint
+func TestRewriteServerHost(t *testing.T) {
+	assert.Equal(t, "https://127.0.0.1:40123", rewriteServerHost("https://127.0.0.1:40123", ""))
+	assert.Equal(t, "https://docker-host.example.com:40123", rewriteServerHost("https://127.0.0.1:40123", "docker-host.example.com"))
+	assert.Equal(t, "https://[fd00::1]:40123", rewriteServerHost("https://127.0.0.1:40123", "fd00::1"))
+	assert.Equal(t, "https://docker-host.example.com", rewriteServerHost("https://127.0.0.1", "docker-host.example.com"))
+}
+
+// TestRewriteKubeconfigServer tests pointing the kubeconfig to the external host
+func TestRewriteKubeconfigServer(t *testing.T) {
+	kubeconfig := "clusters:\n- cluster:\n    server: https://127.0.0.1:40123\n  name: kind-test\n"
+
+	assert.Equal(t, kubeconfig, rewriteKubeconfigServer(kubeconfig, "https://127.0.0.1:40123", ""))
+	assert.Equal(t, "clusters:\n- cluster:\n    server: https://docker-host.example.com:40123\n  name: kind-test\n",
+		rewriteKubeconfigServer(kubeconfig, "https://127.0.0.1:40123", "docker-host.example.com"))
+}
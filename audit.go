@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// auditPolicyPath is the directory the audit policy is mounted to on the
+	// control-plane nodes and in the API server.
+	auditPolicyPath = "/etc/kubernetes/audit"
+	// auditLogPath is the directory the API server writes audit logs to.
+	auditLogPath = "/var/log/kubernetes/audit"
+)
+
+// validateAuditPolicy checks that the policy is an audit Policy document.
+func validateAuditPolicy(v interface{}, k string) ([]string, []error) {
+	var policy struct {
+		Kind string `yaml:"kind"`
+	}
+	if err := yaml.Unmarshal([]byte(v.(string)), &policy); err != nil {
+		return nil, []error{fmt.Errorf("%s is not valid YAML: %s", k, err)}
+	}
+	if policy.Kind != "Policy" {
+		return nil, []error{fmt.Errorf("%s must be an audit.k8s.io Policy, got kind %q", k, policy.Kind)}
+	}
+	return nil, nil
+}
+
+// auditLogDir returns the host directory the audit logs of a cluster are
+// written to, with a subdirectory per control-plane node. The default lies
+// outside the cluster data directory, so audit trails outlive the cluster.
+func auditLogDir(d *schema.ResourceData, audit map[string]interface{}) (string, error) {
+	if logDir := audit["log_dir"].(string); logDir != "" {
+		return filepath.Abs(logDir)
+	}
+	dir, err := providerDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit-logs", d.Get("name").(string)), nil
+}
+
+// controlPlaneNodeSuffix returns the suffix kind names the nth control-plane
+// node container with, e.g. control-plane2 for the second one.
+func controlPlaneNodeSuffix(index int) string {
+	if index == 0 {
+		return "control-plane"
+	}
+	return fmt.Sprintf("control-plane%d", index+1)
+}
+
+// prepareAudit writes the audit policy and creates the log directories on the
+// host, which have to exist before the nodes mount them. It returns the host
+// path of the audit log of the first control-plane node.
+func prepareAudit(d *schema.ResourceData) (string, error) {
+	audit := getBlock(d, "audit")
+	if audit == nil {
+		return "", nil
+	}
+
+	if _, err := writeClusterFile(d.Get("name").(string), filepath.Join("audit-policy", "policy.yaml"), audit["policy"].(string), 0600); err != nil {
+		return "", fmt.Errorf("failed to write audit policy: %s", err)
+	}
+
+	logDir, err := auditLogDir(d, audit)
+	if err != nil {
+		return "", err
+	}
+	config, err := generateKindConfig(d)
+	if err != nil {
+		return "", err
+	}
+	for i := range controlPlaneNodes(config) {
+		if err := os.MkdirAll(filepath.Join(logDir, controlPlaneNodeSuffix(i)), 0700); err != nil {
+			return "", fmt.Errorf("failed to create audit log directory: %s", err)
+		}
+	}
+	return filepath.Join(logDir, controlPlaneNodeSuffix(0), "audit.log"), nil
+}
+
+// configureAudit mounts the audit policy and a log directory into every
+// control-plane node and adds the audit flags of the API server.
+func configureAudit(d *schema.ResourceData, config map[string]interface{}, apiServer *apiServerConfig) error {
+	audit := getBlock(d, "audit")
+	if audit == nil {
+		return nil
+	}
+
+	dataDir, err := clusterDataDir(d.Get("name").(string))
+	if err != nil {
+		return err
+	}
+	logDir, err := auditLogDir(d, audit)
+	if err != nil {
+		return err
+	}
+
+	for i, node := range controlPlaneNodes(config) {
+		appendNodeExtraMount(node, filepath.Join(dataDir, "audit-policy"), auditPolicyPath, true)
+		appendNodeExtraMount(node, filepath.Join(logDir, controlPlaneNodeSuffix(i)), auditLogPath, false)
+	}
+
+	apiServer.ExtraArgs["audit-policy-file"] = auditPolicyPath + "/policy.yaml"
+	apiServer.ExtraArgs["audit-log-path"] = auditLogPath + "/audit.log"
+	apiServer.ExtraArgs["audit-log-maxage"] = strconv.Itoa(audit["max_age"].(int))
+	apiServer.ExtraArgs["audit-log-maxsize"] = strconv.Itoa(audit["max_size"].(int))
+	apiServer.ExtraVolumes = append(apiServer.ExtraVolumes,
+		apiServerVolume{
+			Name:      "audit-policy",
+			HostPath:  auditPolicyPath,
+			MountPath: auditPolicyPath,
+			ReadOnly:  true,
+			PathType:  "DirectoryOrCreate",
+		},
+		apiServerVolume{
+			Name:      "audit-logs",
+			HostPath:  auditLogPath,
+			MountPath: auditLogPath,
+			PathType:  "DirectoryOrCreate",
+		},
+	)
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAuditPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+- level: Metadata
+`
+
+// TestValidateAuditPolicy tests that only audit Policy documents are accepted
+func TestValidateAuditPolicy(t *testing.T) {
+	_, errs := validateAuditPolicy(testAuditPolicy, "policy")
+	assert.Empty(t, errs)
+
+	_, errs = validateAuditPolicy("apiVersion: v1\nkind: ConfigMap\n", "policy")
+	assert.NotEmpty(t, errs)
+
+	_, errs = validateAuditPolicy("rules: [", "policy")
+	assert.NotEmpty(t, errs)
+}
+
+// TestControlPlaneNodeSuffix tests matching kind's node container names
+func TestControlPlaneNodeSuffix(t *testing.T) {
+	assert.Equal(t, "control-plane", controlPlaneNodeSuffix(0))
+	assert.Equal(t, "control-plane2", controlPlaneNodeSuffix(1))
+	assert.Equal(t, "control-plane3", controlPlaneNodeSuffix(2))
+}
+
+func testAuditResourceData(t *testing.T, logDir string) *schema.ResourceData {
+	return schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"audit": []interface{}{
+			map[string]interface{}{
+				"policy":  testAuditPolicy,
+				"log_dir": logDir,
+			},
+		},
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"node": []interface{}{
+					map[string]interface{}{"role": "control-plane"},
+					map[string]interface{}{"role": "control-plane"},
+					map[string]interface{}{"role": "worker"},
+				},
+			},
+		},
+	})
+}
+
+// TestPrepareAudit tests writing the policy and creating a log directory per control-plane node
+func TestPrepareAudit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	auditLog, err := prepareAudit(testAuditResourceData(t, ""))
+	require.NoError(t, err)
+
+	dataDir := filepath.Join(home, ".terraform-provider-kind", "clusters", "test")
+	logDir := filepath.Join(home, ".terraform-provider-kind", "audit-logs", "test")
+	assert.Equal(t, filepath.Join(logDir, "control-plane", "audit.log"), auditLog)
+
+	policy, err := os.ReadFile(filepath.Join(dataDir, "audit-policy", "policy.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, testAuditPolicy, string(policy))
+
+	assert.DirExists(t, filepath.Join(logDir, "control-plane"))
+	assert.DirExists(t, filepath.Join(logDir, "control-plane2"))
+	assert.NoDirExists(t, filepath.Join(logDir, "control-plane3"))
+
+	// Audit trails outlive the cluster
+	require.NoError(t, removeClusterDataDir("test"))
+	assert.NoDirExists(t, dataDir)
+	assert.DirExists(t, filepath.Join(logDir, "control-plane"))
+}
+
+// TestConfigureAudit tests the generated audit flags and node mounts
+func TestConfigureAudit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	logDir := t.TempDir()
+
+	config := testKindConfig(t, testAuditResourceData(t, logDir))
+
+	assert.Equal(t, []string{`kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    audit-log-maxage: "7"
+    audit-log-maxsize: "100"
+    audit-log-path: /var/log/kubernetes/audit/audit.log
+    audit-policy-file: /etc/kubernetes/audit/policy.yaml
+  extraVolumes:
+  - name: audit-logs
+    hostPath: /var/log/kubernetes/audit
+    mountPath: /var/log/kubernetes/audit
+    readOnly: false
+    pathType: DirectoryOrCreate
+  - name: audit-policy
+    hostPath: /etc/kubernetes/audit
+    mountPath: /etc/kubernetes/audit
+    readOnly: true
+    pathType: DirectoryOrCreate
+`}, config["kubeadmConfigPatches"])
+
+	policyDir := filepath.Join(home, ".terraform-provider-kind", "clusters", "test", "audit-policy")
+	nodes := config["nodes"].([]map[string]interface{})
+	require.Len(t, nodes, 3)
+	assert.Equal(t, []map[string]interface{}{
+		{"hostPath": policyDir, "containerPath": "/etc/kubernetes/audit", "readOnly": true},
+		{"hostPath": filepath.Join(logDir, "control-plane"), "containerPath": "/var/log/kubernetes/audit", "readOnly": false},
+	}, nodes[0]["extraMounts"])
+	assert.Equal(t, []map[string]interface{}{
+		{"hostPath": policyDir, "containerPath": "/etc/kubernetes/audit", "readOnly": true},
+		{"hostPath": filepath.Join(logDir, "control-plane2"), "containerPath": "/var/log/kubernetes/audit", "readOnly": false},
+	}, nodes[1]["extraMounts"])
+	assert.Nil(t, nodes[2]["extraMounts"])
+}
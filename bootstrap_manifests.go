@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// applyBootstrapManifests applies the bootstrap_manifests entries of a
+// cluster, in order, with server-side apply.
+func applyBootstrapManifests(config *ProviderConfig, clusterName string, entries []string) diag.Diagnostics {
+	var documents []manifestDocument
+	for i, entry := range entries {
+		content, err := resolveManifestSource(entry)
+		if err != nil {
+			return diag.Errorf("Failed to read bootstrap_manifests.%d: %s", i, err)
+		}
+		parsed, err := parseManifest(content)
+		if err != nil {
+			return diag.Errorf("Failed to parse bootstrap_manifests.%d: %s", i, err)
+		}
+		documents = append(documents, parsed...)
+	}
+	if len(documents) == 0 {
+		return nil
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	// The API server may still be starting when wait_for_ready is off
+	if err := waitForAPIServer(kubeconfigPath); err != nil {
+		return diag.Errorf("Failed to apply bootstrap manifests: %s", err)
+	}
+
+	log.Printf("[INFO] Applying %d bootstrap objects to Kind cluster %s", len(documents), clusterName)
+	return bootstrapStatusDiagnostics(documents, applyManifestObjects(kubeconfigPath, documents))
+}
+
+// bootstrapStatusDiagnostics reports the status of the bootstrap objects.
+// When objects could not be applied, a warning lists each object with its
+// result, followed by an error for each failed object. Clean applies are only
+// logged.
+func bootstrapStatusDiagnostics(documents []manifestDocument, errs []error) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var status []string
+	applied := 0
+	for i, document := range documents {
+		if errs[i] != nil {
+			diags = append(diags, applyErrorDiagnostic(document, errs[i]))
+			status = append(status, fmt.Sprintf("%s: failed", document.Object))
+			continue
+		}
+		applied++
+		status = append(status, fmt.Sprintf("%s: applied", document.Object))
+	}
+
+	if len(diags) == 0 {
+		log.Printf("[INFO] Applied %d bootstrap objects", applied)
+		return nil
+	}
+	return append(diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Applied %d of %d bootstrap objects", applied, len(documents)),
+		Detail:   strings.Join(status, "\n"),
+	}}, diags...)
+}
+
+// setBootstrapManifestsHash plans re-applying the bootstrap manifests when
+// their content, including the content of referenced files, changed.
+func setBootstrapManifestsHash(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("bootstrap_manifests") {
+		return d.SetNewComputed("bootstrap_manifests_hash")
+	}
+
+	hash, err := hashManifests(expandStringList(d.Get("bootstrap_manifests").([]interface{})))
+	if err != nil {
+		return fmt.Errorf("failed to read bootstrap_manifests: %s", err)
+	}
+	if d.Get("bootstrap_manifests_hash").(string) == hash {
+		return nil
+	}
+	return d.SetNew("bootstrap_manifests_hash", hash)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// providerDataDir returns the host directory below which the provider keeps
+// the files it generates.
+func providerDataDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %s", err)
+	}
+	return filepath.Join(home, ".terraform-provider-kind"), nil
+}
+
+// clusterDataDir returns the host directory holding the files the provider
+// generates for a cluster, e.g. policies mounted into the nodes. It is removed
+// when the cluster is deleted.
+func clusterDataDir(clusterName string) (string, error) {
+	dir, err := providerDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "clusters", clusterName), nil
+}
+
+// writeClusterFile writes a generated file below the data directory of a
+// cluster and returns its path.
+func writeClusterFile(clusterName, name, content string, perm os.FileMode) (string, error) {
+	dir, err := clusterDataDir(clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// removeClusterDataDir removes the generated files of a cluster.
+func removeClusterDataDir(clusterName string) error {
+	dir, err := clusterDataDir(clusterName)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
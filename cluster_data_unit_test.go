@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClusterDataDir tests writing and removing the generated files of a cluster
+func TestClusterDataDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir, err := clusterDataDir("test")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".terraform-provider-kind", "clusters", "test"), dir)
+
+	path, err := writeClusterFile("test", filepath.Join("policies", "policy.yaml"), "kind: Policy\n", 0600)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "policies", "policy.yaml"), path)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	require.NoError(t, removeClusterDataDir("test"))
+	assert.NoDirExists(t, dir)
+
+	// Removing is idempotent
+	assert.NoError(t, removeClusterDataDir("test"))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getClusterNodes returns the names of the node containers of a Kind cluster.
+func getClusterNodes(config *ProviderConfig, clusterName string) ([]string, error) {
+	cmd := kindCommand(config, "get", "nodes", "--name", clusterName)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes of cluster %s: %s", clusterName, err)
+	}
+
+	var nodes []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		line = strings.TrimSpace(line)
+		// kind reports an empty cluster on stdout instead of failing
+		if line == "" || strings.HasPrefix(line, "No kind nodes") {
+			continue
+		}
+		nodes = append(nodes, line)
+	}
+	return nodes, nil
+}
+
+// clusterRunning reports whether all node containers of a cluster are running.
+func clusterRunning(config *ProviderConfig, clusterName string) (bool, error) {
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return false, err
+	}
+	if len(nodes) == 0 {
+		return false, nil
+	}
+
+	args := append([]string{"inspect", "--format", "{{.State.Running}}"}, nodes...)
+	output, err := dockerCommand(config, args...).Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect nodes of cluster %s: %s", clusterName, err)
+	}
+
+	return allNodesRunning(string(output)), nil
+}
+
+// allNodesRunning parses the output of docker inspect with a
+// {{.State.Running}} format, one line per container.
+func allNodesRunning(output string) bool {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return false
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "true" {
+			return false
+		}
+	}
+	return true
+}
+
+// stopClusterNodes stops all node containers of a cluster without removing them.
+func stopClusterNodes(config *ProviderConfig, clusterName string) error {
+	return runOnClusterNodes(config, clusterName, "stop")
+}
+
+// startClusterNodes starts all previously stopped node containers of a cluster.
+func startClusterNodes(config *ProviderConfig, clusterName string) error {
+	return runOnClusterNodes(config, clusterName, "start")
+}
+
+func runOnClusterNodes(config *ProviderConfig, clusterName, action string) error {
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("cluster %s has no nodes", clusterName)
+	}
+
+	args := append([]string{action}, nodes...)
+	output, err := dockerCommand(config, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to %s nodes of cluster %s: %s\nOutput: %s", action, clusterName, err, string(output))
+	}
+	return nil
+}
+
+// execOnClusterNodes runs a command in every node container of a cluster.
+func execOnClusterNodes(config *ProviderConfig, clusterName string, command ...string) error {
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		args := append([]string{"exec", node}, command...)
+		output, err := dockerCommand(config, args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to run %s on node %s: %s\nOutput: %s", strings.Join(command, " "), node, err, string(output))
+		}
+	}
+	return nil
+}
+
+// writeFileOnClusterNodes writes a file readable only by root into every
+// node container of a cluster.
+func writeFileOnClusterNodes(config *ProviderConfig, clusterName, path, content string) error {
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		cmd := dockerCommand(config, "exec", "-i", node, "sh", "-c",
+			`mkdir -p "$(dirname "$1")" && umask 077 && cat > "$1"`, "sh", path)
+		cmd.Stdin = strings.NewReader(content)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to write %s on node %s: %s\nOutput: %s", path, node, err, string(output))
+		}
+	}
+	return nil
+}
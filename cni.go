@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// cniWorkloads lists the workloads that roll out once a known CNI is
+// installed. Custom CNIs are only checked through node readiness.
+var cniWorkloads = map[string][]string{
+	"calico": {"daemonset/calico-node"},
+	"cilium": {"daemonset/cilium"},
+}
+
+// configureCNI disables the default CNI when a CNI is installed by the
+// provider.
+func configureCNI(d *schema.ResourceData, config map[string]interface{}) {
+	if getBlock(d, "cni") == nil {
+		return
+	}
+
+	networking, _ := config["networking"].(map[string]interface{})
+	if networking == nil {
+		networking = map[string]interface{}{}
+	}
+	networking["disableDefaultCNI"] = true
+	config["networking"] = networking
+}
+
+// installCNI loads the CNI images into all nodes and applies the CNI
+// manifest. It runs right after cluster creation, as nodes stay NotReady
+// until a CNI is installed.
+func installCNI(d *schema.ResourceData, config *ProviderConfig, clusterName string) diag.Diagnostics {
+	cni := getBlock(d, "cni")
+	if cni == nil {
+		return nil
+	}
+	cniType := cni["type"].(string)
+
+	for _, image := range expandStringList(cni["images"].([]interface{})) {
+		if err := loadImageIntoCluster(config, clusterName, image); err != nil {
+			return diag.Errorf("Failed to load CNI image: %s", err)
+		}
+	}
+
+	manifest, err := os.ReadFile(cni["manifest_path"].(string))
+	if err != nil {
+		return diag.Errorf("Failed to read CNI manifest: %s", err)
+	}
+
+	log.Printf("[INFO] Installing %s CNI into Kind cluster %s", cniType, clusterName)
+
+	return installAddon(config, clusterName, string(manifest), "kube-system", cniWorkloads[cniType]...)
+}
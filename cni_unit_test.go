@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerateKindConfig_networking tests that only configured networking settings are rendered
+func TestGenerateKindConfig_networking(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"networking": []interface{}{
+					map[string]interface{}{
+						"pod_subnet":      "10.244.0.0/16",
+						"kube_proxy_mode": "ipvs",
+					},
+				},
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, map[string]interface{}{
+		"podSubnet":     "10.244.0.0/16",
+		"kubeProxyMode": "ipvs",
+	}, config["networking"])
+}
+
+// TestConfigureCNI tests that the default CNI is disabled when a CNI is installed
+func TestConfigureCNI(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"cni": []interface{}{
+			map[string]interface{}{
+				"type":          "calico",
+				"manifest_path": "calico.yaml",
+			},
+		},
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"networking": []interface{}{
+					map[string]interface{}{
+						"pod_subnet": "192.168.0.0/16",
+					},
+				},
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, map[string]interface{}{
+		"podSubnet":         "192.168.0.0/16",
+		"disableDefaultCNI": true,
+	}, config["networking"])
+}
+
+// TestConfigureCNI_notConfigured tests that the default CNI is kept without a cni block
+func TestConfigureCNI_notConfigured(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.NotContains(t, config, "networking")
+}
+
+// TestCNISchema tests the validation of the cni type
+func TestCNISchema(t *testing.T) {
+	s := resourceKindCluster().Schema["cni"]
+	assert.True(t, s.ForceNew)
+
+	validate := s.Elem.(*schema.Resource).Schema["type"].ValidateFunc
+	for _, cniType := range []string{"calico", "cilium", "custom"} {
+		_, errs := validate(cniType, "type")
+		assert.Empty(t, errs, cniType)
+	}
+	_, errs := validate("flannel", "type")
+	assert.NotEmpty(t, errs)
+}
+
+// TestNetworkingSchema tests that networking changes recreate the cluster, as they can't be applied in place
+func TestNetworkingSchema(t *testing.T) {
+	kindConfig := resourceKindCluster().Schema["kind_config"].Elem.(*schema.Resource)
+	networking := kindConfig.Schema["networking"].Elem.(*schema.Resource)
+
+	for _, key := range []string{"disable_default_cni", "pod_subnet", "service_subnet", "api_server_address", "api_server_port", "kube_proxy_mode"} {
+		assert.True(t, networking.Schema[key].ForceNew, key)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// kindCommand builds a kind CLI invocation that targets the Docker daemon
+// configured on the provider.
+func kindCommand(config *ProviderConfig, args ...string) *exec.Cmd {
+	cmd := exec.Command("kind", args...)
+	cmd.Env = commandEnv(config)
+	return cmd
+}
+
+// commandEnv returns the environment for CLI invocations. A nil result
+// means the command inherits the provider's own environment.
+func commandEnv(config *ProviderConfig) []string {
+	if config == nil || (config.DockerHost == "" && !config.TLS.enabled()) {
+		return nil
+	}
+	env := os.Environ()
+	if config.DockerHost != "" {
+		env = append(env, fmt.Sprintf("DOCKER_HOST=%s", config.DockerHost))
+	}
+	return append(env, config.TLS.env()...)
+}
+
+// withEnv adds environment variables to a command on top of the environment
+// it would otherwise run with.
+func withEnv(cmd *exec.Cmd, env ...string) *exec.Cmd {
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, env...)
+	return cmd
+}
+
+// dockerCommand builds a docker CLI invocation that targets the Docker daemon
+// configured on the provider.
+func dockerCommand(config *ProviderConfig, args ...string) *exec.Cmd {
+	cmd := exec.Command("docker", args...)
+	cmd.Env = commandEnv(config)
+	return cmd
+}
+
+// kubectlCommand builds a kubectl invocation against the cluster described by
+// a kubeconfig file.
+func kubectlCommand(kubeconfigPath string, args ...string) *exec.Cmd {
+	return exec.Command("kubectl", append([]string{"--kubeconfig", kubeconfigPath}, args...)...)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKindNodeImage() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceKindNodeImageRead,
+
+		Schema: map[string]*schema.Schema{
+			"kubernetes_version": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Kubernetes version, e.g. 1.29 or 1.29.2",
+			},
+			"kind_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "kind release to pick the image for, e.g. v0.20.0. Defaults to the installed kind CLI",
+			},
+			"check_local": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Check whether the image is already present in the local image cache",
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Recommended node image reference, pinned by digest",
+			},
+			"digest": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "sha256 digest of the node image",
+			},
+			"present_locally": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the image is present in the local image cache. Only set when check_local is enabled",
+			},
+		},
+	}
+}
+
+func dataSourceKindNodeImageRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	kubernetesVersion := d.Get("kubernetes_version").(string)
+
+	kindVersion := d.Get("kind_version").(string)
+	if kindVersion == "" {
+		detected, err := getKindVersion(config)
+		if err != nil {
+			return diag.Errorf("Failed to detect kind version: %s", err)
+		}
+		kindVersion = detected
+	}
+
+	image, err := lookupNodeImage(config, kindVersion, kubernetesVersion)
+	if err != nil {
+		return diag.Errorf("Failed to resolve node image: %s", err)
+	}
+
+	log.Printf("[INFO] Resolved Kubernetes %s for kind %s to node image %s", kubernetesVersion, kindVersion, image)
+
+	d.SetId(image)
+	d.Set("kind_version", kindVersion)
+	d.Set("image", image)
+	d.Set("digest", imageDigest(image))
+
+	presentLocally := false
+	if d.Get("check_local").(bool) {
+		presentLocally = imagePresentLocally(config, imageRepository(image)+"@"+imageDigest(image))
+	}
+	d.Set("present_locally", presentLocally)
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDataSourceKindNodeImageRead tests resolving a node image for an explicit kind release
+func TestDataSourceKindNodeImageRead(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceKindNodeImage().Schema, map[string]interface{}{
+		"kubernetes_version": "1.27",
+		"kind_version":       "v0.20.0",
+	})
+
+	diags := dataSourceKindNodeImageRead(context.Background(), d, &ProviderConfig{})
+	require.False(t, diags.HasError(), "%v", diags)
+
+	expected := "kindest/node:v1.27.3@sha256:3966ac761ae0136263ffdb6cfd4db23ef8a83cba8a463690e98317add2c9ba72"
+	assert.Equal(t, expected, d.Id())
+	assert.Equal(t, expected, d.Get("image"))
+	assert.Equal(t, "sha256:3966ac761ae0136263ffdb6cfd4db23ef8a83cba8a463690e98317add2c9ba72", d.Get("digest"))
+	assert.Equal(t, false, d.Get("present_locally"))
+}
+
+// TestDataSourceKindNodeImageRead_unknownVersion tests the error for versions missing from the table
+func TestDataSourceKindNodeImageRead_unknownVersion(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceKindNodeImage().Schema, map[string]interface{}{
+		"kubernetes_version": "1.12",
+		"kind_version":       "v0.20.0",
+	})
+
+	diags := dataSourceKindNodeImageRead(context.Background(), d, &ProviderConfig{})
+	assert.True(t, diags.HasError())
+}
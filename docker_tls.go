@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dockerTLS holds the TLS settings of the Docker daemon, passed to the docker
+// and kind CLIs through the environment.
+type dockerTLS struct {
+	// CertPath is the directory holding ca.pem, cert.pem and key.pem
+	CertPath string
+	Verify   bool
+}
+
+// enabled reports whether the daemon is reached over TLS.
+func (t dockerTLS) enabled() bool {
+	return t.CertPath != "" || t.Verify
+}
+
+// env returns the environment variables that make the docker CLI use the TLS
+// settings. DOCKER_TLS_VERIFY is always set so a verification disabled on the
+// provider is not re-enabled by the provider's own environment.
+func (t dockerTLS) env() []string {
+	if !t.enabled() {
+		return nil
+	}
+
+	verify := ""
+	if t.Verify {
+		verify = "1"
+	}
+	env := []string{"DOCKER_TLS=1", "DOCKER_TLS_VERIFY=" + verify}
+	if t.CertPath != "" {
+		env = append(env, "DOCKER_CERT_PATH="+t.CertPath)
+	}
+	return env
+}
+
+// envBoolDefault returns a schema default that is true when the environment
+// variable is set to a non-empty value, as the docker CLI interprets it.
+func envBoolDefault(name string) schema.SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		return os.Getenv(name) != "", nil
+	}
+}
+
+// validatePrivateKey validates a PEM encoded private key.
+func validatePrivateKey(v interface{}, k string) ([]string, []error) {
+	block, _ := pem.Decode([]byte(v.(string)))
+	if block == nil || !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+		return nil, []error{fmt.Errorf("%s must be a PEM encoded PRIVATE KEY", k)}
+	}
+	return nil, nil
+}
+
+// validateCertificate validates a PEM encoded client certificate, rejecting
+// CA certificates and certificates not usable for client authentication.
+func validateCertificate(v interface{}, k string) ([]string, []error) {
+	block, _ := pem.Decode([]byte(v.(string)))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, []error{fmt.Errorf("%s must be a PEM encoded CERTIFICATE", k)}
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid certificate: %s", k, err)}
+	}
+	if certificate.IsCA {
+		return nil, []error{fmt.Errorf("%s is a CA certificate, expected a client certificate", k)}
+	}
+	if len(certificate.ExtKeyUsage) == 0 {
+		return nil, nil
+	}
+	for _, usage := range certificate.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageClientAuth || usage == x509.ExtKeyUsageAny {
+			return nil, nil
+		}
+	}
+	return nil, []error{fmt.Errorf("%s is not valid for client authentication", k)}
+}
+
+// expandDockerTLS reads the TLS settings of the provider. Inline certificate
+// material takes precedence over docker_cert_path and is written to a
+// temporary directory the docker CLI can read.
+func expandDockerTLS(d *schema.ResourceData) (dockerTLS, error) {
+	tls := dockerTLS{
+		CertPath: d.Get("docker_cert_path").(string),
+		Verify:   d.Get("docker_tls_verify").(bool),
+	}
+
+	caMaterial := d.Get("ca_material").(string)
+	certMaterial := d.Get("cert_material").(string)
+	keyMaterial := d.Get("key_material").(string)
+	if caMaterial == "" && certMaterial == "" && keyMaterial == "" {
+		return tls, nil
+	}
+	if (certMaterial == "") != (keyMaterial == "") {
+		return tls, fmt.Errorf("cert_material and key_material must be set together")
+	}
+	if tls.Verify && caMaterial == "" {
+		return tls, fmt.Errorf("ca_material is required to verify the Docker daemon with inline certificate material")
+	}
+
+	certPath, err := writeDockerCertMaterial(caMaterial, certMaterial, keyMaterial)
+	if err != nil {
+		return tls, fmt.Errorf("failed to write Docker certificate material: %s", err)
+	}
+	tls.CertPath = certPath
+	return tls, nil
+}
+
+// dockerCertDirs caches the directories inline certificate material was
+// written to by this provider process, keyed by a hash of the material.
+var (
+	dockerCertDirs   = map[string]string{}
+	dockerCertDirsMu sync.Mutex
+)
+
+// writeDockerCertMaterial writes inline certificate material to a private
+// temporary directory of this provider process and returns the directory.
+// The material is written once per process, as Terraform configures the
+// provider repeatedly, and removed by removeDockerCertMaterial when the
+// process exits.
+func writeDockerCertMaterial(caMaterial, certMaterial, keyMaterial string) (string, error) {
+	sum := sha256.Sum256([]byte(caMaterial + "\x00" + certMaterial + "\x00" + keyMaterial))
+	key := hex.EncodeToString(sum[:])
+
+	dockerCertDirsMu.Lock()
+	defer dockerCertDirsMu.Unlock()
+	if dir, ok := dockerCertDirs[key]; ok {
+		return dir, nil
+	}
+
+	// MkdirTemp creates the directory readable by the current user only
+	dir, err := os.MkdirTemp("", "terraform-provider-kind-docker-certs-*")
+	if err != nil {
+		return "", err
+	}
+
+	files := map[string]string{
+		"ca.pem":   caMaterial,
+		"cert.pem": certMaterial,
+		"key.pem":  keyMaterial,
+	}
+	for name, content := range files {
+		if content == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	dockerCertDirs[key] = dir
+	return dir, nil
+}
+
+// removeDockerCertMaterial removes the directories inline certificate
+// material was written to, so private keys don't outlive the provider
+// process.
+func removeDockerCertMaterial() {
+	dockerCertDirsMu.Lock()
+	defer dockerCertDirsMu.Unlock()
+	for key, dir := range dockerCertDirs {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("[WARN] Failed to remove Docker certificate material %s: %s", dir, err)
+		}
+		delete(dockerCertDirs, key)
+	}
+}
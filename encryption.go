@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// encryptionConfigPath is the directory the EncryptionConfiguration is
+	// mounted to on the control-plane nodes and in the API server.
+	encryptionConfigPath = "/etc/kubernetes/encryption"
+	// encryptionCheckSecret is the Secret written to verify encryption.
+	encryptionCheckSecret = "terraform-provider-kind-encryption-check"
+)
+
+// encryptionKeyProviders are the providers that encrypt with static keys.
+var encryptionKeyProviders = map[string]bool{
+	"aescbc":    true,
+	"aesgcm":    true,
+	"secretbox": true,
+}
+
+// validateEncryptionAtRest checks the settings each provider type requires.
+func validateEncryptionAtRest(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("encryption_at_rest") {
+		return nil
+	}
+	blocks := d.Get("encryption_at_rest").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+
+	for i, raw := range blocks[0].(map[string]interface{})["provider"].([]interface{}) {
+		if raw == nil {
+			continue
+		}
+		provider := raw.(map[string]interface{})
+		providerType := provider["type"].(string)
+
+		switch {
+		case encryptionKeyProviders[providerType] && len(provider["key"].([]interface{})) == 0:
+			return fmt.Errorf("encryption_at_rest provider %d: %s requires at least one key", i, providerType)
+		case providerType == "kms" && (provider["kms_name"].(string) == "" || provider["kms_endpoint"].(string) == ""):
+			return fmt.Errorf("encryption_at_rest provider %d: kms requires kms_name and kms_endpoint", i)
+		}
+	}
+	return nil
+}
+
+// renderEncryptionConfiguration renders the encryption_at_rest block as an
+// EncryptionConfiguration. The first provider encrypts new data, the others
+// are only used to decrypt.
+func renderEncryptionConfiguration(encryption map[string]interface{}) (string, error) {
+	var providers []map[string]interface{}
+	for _, raw := range encryption["provider"].([]interface{}) {
+		provider := raw.(map[string]interface{})
+		providerType := provider["type"].(string)
+
+		settings := map[string]interface{}{}
+		switch {
+		case encryptionKeyProviders[providerType]:
+			var keys []map[string]string
+			for _, key := range provider["key"].([]interface{}) {
+				keyMap := key.(map[string]interface{})
+				keys = append(keys, map[string]string{
+					"name":   keyMap["name"].(string),
+					"secret": keyMap["secret"].(string),
+				})
+			}
+			settings["keys"] = keys
+		case providerType == "kms":
+			settings["apiVersion"] = provider["kms_api_version"].(string)
+			settings["name"] = provider["kms_name"].(string)
+			settings["endpoint"] = provider["kms_endpoint"].(string)
+		}
+		providers = append(providers, map[string]interface{}{providerType: settings})
+	}
+
+	resources := expandStringList(encryption["resources"].([]interface{}))
+	if len(resources) == 0 {
+		resources = []string{"secrets"}
+	}
+
+	data, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "apiserver.config.k8s.io/v1",
+		"kind":       "EncryptionConfiguration",
+		"resources": []map[string]interface{}{
+			{"resources": resources, "providers": providers},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render EncryptionConfiguration: %s", err)
+	}
+	return string(data), nil
+}
+
+// prepareEncryption writes the EncryptionConfiguration to the host before the
+// control-plane nodes mount it.
+func prepareEncryption(d *schema.ResourceData) error {
+	encryption := getBlock(d, "encryption_at_rest")
+	if encryption == nil {
+		return nil
+	}
+
+	content, err := renderEncryptionConfiguration(encryption)
+	if err != nil {
+		return err
+	}
+	if _, err := writeClusterFile(d.Get("name").(string), filepath.Join("encryption", "config.yaml"), content, 0600); err != nil {
+		return fmt.Errorf("failed to write EncryptionConfiguration: %s", err)
+	}
+	return nil
+}
+
+// configureEncryption mounts the EncryptionConfiguration into every
+// control-plane node and points the API server to it.
+func configureEncryption(d *schema.ResourceData, config map[string]interface{}, apiServer *apiServerConfig) error {
+	if getBlock(d, "encryption_at_rest") == nil {
+		return nil
+	}
+
+	dataDir, err := clusterDataDir(d.Get("name").(string))
+	if err != nil {
+		return err
+	}
+
+	for _, node := range controlPlaneNodes(config) {
+		appendNodeExtraMount(node, filepath.Join(dataDir, "encryption"), encryptionConfigPath, true)
+	}
+
+	apiServer.ExtraArgs["encryption-provider-config"] = encryptionConfigPath + "/config.yaml"
+	apiServer.ExtraVolumes = append(apiServer.ExtraVolumes, apiServerVolume{
+		Name:      "encryption-config",
+		HostPath:  encryptionConfigPath,
+		MountPath: encryptionConfigPath,
+		ReadOnly:  true,
+		PathType:  "DirectoryOrCreate",
+	})
+	return nil
+}
+
+// encryptionCheckManifest is the Secret read back from etcd to verify
+// encryption.
+var encryptionCheckManifest = fmt.Sprintf(`apiVersion: v1
+kind: Secret
+metadata:
+  name: %s
+  namespace: default
+stringData:
+  check: plaintext-marker
+`, encryptionCheckSecret)
+
+// verifyEncryption writes a Secret and reads it directly from etcd to check
+// that it is stored encrypted by the first configured provider.
+func verifyEncryption(d *schema.ResourceData, config *ProviderConfig, clusterName string) diag.Diagnostics {
+	encryption := getBlock(d, "encryption_at_rest")
+	if encryption == nil {
+		return nil
+	}
+
+	resources := expandStringList(encryption["resources"].([]interface{}))
+	first := encryption["provider"].([]interface{})[0].(map[string]interface{})["type"].(string)
+	if (len(resources) > 0 && !containsString(resources, "secrets")) || first == "identity" {
+		log.Printf("[INFO] Secrets of Kind cluster %s are not encrypted, skipping verification", clusterName)
+		return nil
+	}
+
+	documents, err := parseManifest(encryptionCheckManifest)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	// The API server may still be starting when wait_for_ready is off
+	if err := waitForAPIServer(kubeconfigPath); err != nil {
+		return diag.Errorf("Failed to verify encryption at rest: %s", err)
+	}
+	if diags := applyManifestDocuments(kubeconfigPath, documents); diags.HasError() {
+		return diags
+	}
+	defer deleteManifestDocument(kubeconfigPath, documents[0])
+
+	stored, err := readEtcdKey(config, clusterName+"-control-plane", "/registry/secrets/default/"+encryptionCheckSecret)
+	if err != nil {
+		return diag.Errorf("Failed to verify encryption at rest: %s", err)
+	}
+	if err := checkEncryptedValue(stored, first); err != nil {
+		return diag.Errorf("Encryption at rest is not effective: %s", err)
+	}
+
+	log.Printf("[INFO] Verified that secrets of Kind cluster %s are encrypted with %s", clusterName, first)
+	return nil
+}
+
+// readEtcdKey reads a key with etcdctl from the etcd container of a
+// control-plane node.
+func readEtcdKey(config *ProviderConfig, node, key string) (string, error) {
+	output, err := dockerCommand(config, "exec", node, "crictl", "ps", "--quiet", "--name", "^etcd$").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find etcd on node %s: %s", node, err)
+	}
+	containers := strings.Fields(string(output))
+	if len(containers) == 0 {
+		return "", fmt.Errorf("etcd is not running on node %s", node)
+	}
+
+	output, err = dockerCommand(config, "exec", node, "crictl", "exec", containers[0], "etcdctl",
+		"--endpoints", "https://127.0.0.1:2379",
+		"--cacert", "/etc/kubernetes/pki/etcd/ca.crt",
+		"--cert", "/etc/kubernetes/pki/etcd/server.crt",
+		"--key", "/etc/kubernetes/pki/etcd/server.key",
+		"get", key, "--print-value-only").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from etcd: %s\nOutput: %s", key, err, string(output))
+	}
+	return string(output), nil
+}
+
+// checkEncryptedValue checks that a value read from etcd carries the
+// envelope prefix of the expected provider and no plaintext.
+func checkEncryptedValue(stored, providerType string) error {
+	if stored == "" {
+		return fmt.Errorf("the Secret was not found in etcd")
+	}
+	prefix := "k8s:enc:" + providerType + ":"
+	if !strings.HasPrefix(stored, prefix) {
+		return fmt.Errorf("the Secret is stored without the %q prefix", prefix)
+	}
+	if strings.Contains(stored, "plaintext-marker") {
+		return fmt.Errorf("the Secret data is stored in plaintext")
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEncryptionKey = "c2VjcmV0LWtleS1mb3ItdGVzdHMtb25seS0zMmJ5dGU="
+
+func testEncryptionConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"name": "test",
+		"encryption_at_rest": []interface{}{
+			map[string]interface{}{
+				"provider": []interface{}{
+					map[string]interface{}{
+						"type": "aescbc",
+						"key": []interface{}{
+							map[string]interface{}{"name": "key1", "secret": testEncryptionKey},
+						},
+					},
+					map[string]interface{}{"type": "identity"},
+				},
+			},
+		},
+	}
+}
+
+// TestRenderEncryptionConfiguration tests rendering providers in order with secrets as the default resource
+func TestRenderEncryptionConfiguration(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, testEncryptionConfig())
+
+	content, err := renderEncryptionConfiguration(getBlock(d, "encryption_at_rest"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- providers:
+  - aescbc:
+      keys:
+      - name: key1
+        secret: `+testEncryptionKey+`
+  - identity: {}
+  resources:
+  - secrets
+`, content)
+}
+
+// TestRenderEncryptionConfiguration_kms tests rendering a KMS provider
+func TestRenderEncryptionConfiguration_kms(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"encryption_at_rest": []interface{}{
+			map[string]interface{}{
+				"resources": []interface{}{"secrets", "configmaps"},
+				"provider": []interface{}{
+					map[string]interface{}{
+						"type":         "kms",
+						"kms_name":     "local-kms",
+						"kms_endpoint": "unix:///var/run/kms.sock",
+					},
+				},
+			},
+		},
+	})
+
+	content, err := renderEncryptionConfiguration(getBlock(d, "encryption_at_rest"))
+	require.NoError(t, err)
+
+	assert.Contains(t, content, `  - kms:
+      apiVersion: v2
+      endpoint: unix:///var/run/kms.sock
+      name: local-kms
+  resources:
+  - secrets
+  - configmaps
+`)
+}
+
+// TestValidateEncryptionAtRest tests the settings required per provider type
+func TestValidateEncryptionAtRest(t *testing.T) {
+	assert.NoError(t, testResourceDiff(t, resourceKindCluster(), testEncryptionConfig()))
+
+	err := testResourceDiff(t, resourceKindCluster(), map[string]interface{}{
+		"name": "test",
+		"encryption_at_rest": []interface{}{
+			map[string]interface{}{
+				"provider": []interface{}{
+					map[string]interface{}{"type": "identity"},
+					map[string]interface{}{"type": "secretbox"},
+				},
+			},
+		},
+	})
+	assert.ErrorContains(t, err, "provider 1: secretbox requires at least one key")
+
+	err = testResourceDiff(t, resourceKindCluster(), map[string]interface{}{
+		"name": "test",
+		"encryption_at_rest": []interface{}{
+			map[string]interface{}{
+				"provider": []interface{}{
+					map[string]interface{}{"type": "kms", "kms_name": "local-kms"},
+				},
+			},
+		},
+	})
+	assert.ErrorContains(t, err, "kms requires kms_name and kms_endpoint")
+}
+
+// TestConfigureEncryption_noHome tests that a missing data directory fails the config generation
+func TestConfigureEncryption_noHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, testEncryptionConfig())
+
+	_, err := generateKindConfig(d)
+	assert.ErrorContains(t, err, "failed to configure encryption at rest")
+}
+
+// TestConfigureEncryption tests writing, mounting and wiring the EncryptionConfiguration
+func TestConfigureEncryption(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, testEncryptionConfig())
+
+	require.NoError(t, prepareEncryption(d))
+	encryptionDir := filepath.Join(home, ".terraform-provider-kind", "clusters", "test", "encryption")
+	info, err := os.Stat(filepath.Join(encryptionDir, "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []string{`kind: ClusterConfiguration
+apiServer:
+  extraArgs:
+    encryption-provider-config: /etc/kubernetes/encryption/config.yaml
+  extraVolumes:
+  - name: encryption-config
+    hostPath: /etc/kubernetes/encryption
+    mountPath: /etc/kubernetes/encryption
+    readOnly: true
+    pathType: DirectoryOrCreate
+`}, config["kubeadmConfigPatches"])
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"role": "control-plane",
+			"extraMounts": []map[string]interface{}{
+				{"hostPath": encryptionDir, "containerPath": "/etc/kubernetes/encryption", "readOnly": true},
+			},
+		},
+	}, config["nodes"])
+}
+
+// TestCheckEncryptedValue tests detecting values stored without encryption
+func TestCheckEncryptedValue(t *testing.T) {
+	assert.NoError(t, checkEncryptedValue("k8s:enc:aescbc:v1:key1:\x8f\x12", "aescbc"))
+	assert.NoError(t, checkEncryptedValue("k8s:enc:kms:v2:local-kms:\x0a\x20", "kms"))
+
+	assert.Error(t, checkEncryptedValue("", "aescbc"))
+	assert.Error(t, checkEncryptedValue("k8s\x00\n\x0cv1\x12\x06Secret plaintext-marker", "aescbc"))
+	assert.Error(t, checkEncryptedValue("k8s:enc:aesgcm:v1:key1:\x8f", "aescbc"))
+}
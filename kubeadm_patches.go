@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+// kubeadmPatchTypes lists the documents of the kubeadm configuration kind
+// generates with the API versions they may be patched as.
+var kubeadmPatchTypes = map[string][]string{
+	"InitConfiguration":      {"kubeadm.k8s.io/v1beta2", "kubeadm.k8s.io/v1beta3", "kubeadm.k8s.io/v1beta4"},
+	"JoinConfiguration":      {"kubeadm.k8s.io/v1beta2", "kubeadm.k8s.io/v1beta3", "kubeadm.k8s.io/v1beta4"},
+	"ClusterConfiguration":   {"kubeadm.k8s.io/v1beta2", "kubeadm.k8s.io/v1beta3", "kubeadm.k8s.io/v1beta4"},
+	"KubeletConfiguration":   {"kubelet.config.k8s.io/v1beta1"},
+	"KubeProxyConfiguration": {"kubeproxy.config.k8s.io/v1alpha1"},
+}
+
+// json6902Operations are the operations of RFC 6902.
+var json6902Operations = map[string]bool{
+	"add": true, "remove": true, "replace": true, "move": true, "copy": true, "test": true,
+}
+
+// validateKubeadmConfigPatches validates the kubeadm patches of all nodes at
+// plan time, instead of failing inside kind create cluster. Patches that are
+// not known yet are validated by kind on apply.
+func validateKubeadmConfigPatches(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	kindConfigs := d.Get("kind_config").([]interface{})
+	if len(kindConfigs) == 0 || kindConfigs[0] == nil {
+		return nil
+	}
+	nodes, _ := kindConfigs[0].(map[string]interface{})["node"].([]interface{})
+	return validateNodePatches(nodes, func(key string) bool {
+		return d.NewValueKnown(fmt.Sprintf("kind_config.0.node.%s", key))
+	})
+}
+
+// validateNodePatches reports the first invalid patch with the index of its
+// node and its index in the node's patches. Patches for which known reports
+// false, e.g. patches rendered from attributes of other resources, are left
+// to kind on apply.
+func validateNodePatches(nodes []interface{}, known func(key string) bool) error {
+	for i, node := range nodes {
+		nodeMap, _ := node.(map[string]interface{})
+		if nodeMap == nil {
+			continue
+		}
+
+		patches, _ := nodeMap["kubeadm_config_patches"].([]interface{})
+		for j, patch := range patches {
+			if !known(fmt.Sprintf("%d.kubeadm_config_patches.%d", i, j)) {
+				continue
+			}
+			patchString, _ := patch.(string)
+			if err := validateKubeadmPatch(patchString); err != nil {
+				return fmt.Errorf("kind_config node %d kubeadm_config_patches %d: %s", i, j, err)
+			}
+		}
+
+		json6902Patches, _ := nodeMap["kubeadm_config_patches_json6902"].([]interface{})
+		for j, patch := range json6902Patches {
+			patchMap, _ := patch.(map[string]interface{})
+			if patchMap == nil || !json6902PatchKnown(known, fmt.Sprintf("%d.kubeadm_config_patches_json6902.%d", i, j)) {
+				continue
+			}
+			if err := validateJSON6902Patch(patchMap); err != nil {
+				return fmt.Errorf("kind_config node %d kubeadm_config_patches_json6902 %d: %s", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// json6902PatchKnown reports whether all settings of a JSON 6902 patch are
+// known, since unknown settings read as empty strings.
+func json6902PatchKnown(known func(key string) bool, key string) bool {
+	for _, field := range []string{"group", "version", "kind", "patch"} {
+		if !known(key + "." + field) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateKubeadmPatch checks that a patch is YAML and targets a known
+// kubeadm document.
+func validateKubeadmPatch(patch string) error {
+	var header struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+	}
+	if err := yaml.Unmarshal([]byte(patch), &header); err != nil {
+		return fmt.Errorf("invalid YAML: %s", err)
+	}
+	if header.Kind == "" {
+		return fmt.Errorf("kind is required")
+	}
+	return validateKubeadmPatchTarget(header.APIVersion, header.Kind)
+}
+
+// validateKubeadmPatchTarget checks kind and, when set, apiVersion against
+// the known kubeadm documents.
+func validateKubeadmPatchTarget(apiVersion, kind string) error {
+	versions, ok := kubeadmPatchTypes[kind]
+	if !ok {
+		return fmt.Errorf("unknown kind %q, expected one of %s", kind, strings.Join(kubeadmPatchKinds(), ", "))
+	}
+	if apiVersion == "" {
+		return nil
+	}
+	for _, version := range versions {
+		if version == apiVersion {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown apiVersion %q for %s, expected one of %s", apiVersion, kind, strings.Join(versions, ", "))
+}
+
+func kubeadmPatchKinds() []string {
+	return []string{"ClusterConfiguration", "InitConfiguration", "JoinConfiguration", "KubeletConfiguration", "KubeProxyConfiguration"}
+}
+
+// validateJSON6902Patch checks the target and the operations of a JSON 6902
+// patch.
+func validateJSON6902Patch(patch map[string]interface{}) error {
+	group, _ := patch["group"].(string)
+	version, _ := patch["version"].(string)
+	kind, _ := patch["kind"].(string)
+	if err := validateKubeadmPatchTarget(fmt.Sprintf("%s/%s", group, version), kind); err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so both notations parse
+	var operations []struct {
+		Op   string `yaml:"op"`
+		Path string `yaml:"path"`
+	}
+	patchString, _ := patch["patch"].(string)
+	if err := yaml.Unmarshal([]byte(patchString), &operations); err != nil {
+		return fmt.Errorf("patch must be a list of operations: %s", err)
+	}
+	if len(operations) == 0 {
+		return fmt.Errorf("patch has no operations")
+	}
+	for i, operation := range operations {
+		if !json6902Operations[operation.Op] {
+			return fmt.Errorf("operation %d: unknown op %q", i, operation.Op)
+		}
+		if !strings.HasPrefix(operation.Path, "/") {
+			return fmt.Errorf("operation %d: path %q must start with /", i, operation.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateKubeadmPatch tests validating patches against the known kubeadm documents
+func TestValidateKubeadmPatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		patch       string
+		expectError string
+	}{
+		{name: "kind only", patch: "kind: InitConfiguration\nnodeRegistration:\n  kubeletExtraArgs:\n    node-labels: \"ingress-ready=true\""},
+		{name: "with apiVersion", patch: "apiVersion: kubeadm.k8s.io/v1beta3\nkind: ClusterConfiguration\n"},
+		{name: "kubelet", patch: "apiVersion: kubelet.config.k8s.io/v1beta1\nkind: KubeletConfiguration\nmaxPods: 50\n"},
+		{name: "invalid yaml", patch: "kind: InitConfiguration\n  nodeRegistration: [", expectError: "invalid YAML"},
+		{name: "missing kind", patch: "nodeRegistration: {}\n", expectError: "kind is required"},
+		{name: "unknown kind", patch: "kind: InitConfig\n", expectError: `unknown kind "InitConfig"`},
+		{name: "mismatched apiVersion", patch: "apiVersion: kubelet.config.k8s.io/v1beta1\nkind: JoinConfiguration\n", expectError: `unknown apiVersion "kubelet.config.k8s.io/v1beta1" for JoinConfiguration`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKubeadmPatch(tt.patch)
+			if tt.expectError != "" {
+				assert.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestValidateJSON6902Patch tests validating the target and operations of JSON 6902 patches
+func TestValidateJSON6902Patch(t *testing.T) {
+	patch := func(kind, operations string) map[string]interface{} {
+		return map[string]interface{}{
+			"group":   "kubeadm.k8s.io",
+			"version": "v1beta3",
+			"kind":    kind,
+			"patch":   operations,
+		}
+	}
+
+	assert.NoError(t, validateJSON6902Patch(patch("ClusterConfiguration", "- op: add\n  path: /apiServer/certSANs/-\n  value: kind.example.com\n")))
+	assert.NoError(t, validateJSON6902Patch(patch("ClusterConfiguration", `[{"op": "remove", "path": "/apiServer/timeoutForControlPlane"}]`)))
+
+	assert.ErrorContains(t, validateJSON6902Patch(patch("Cluster", "- op: add\n  path: /a\n")), `unknown kind "Cluster"`)
+	assert.ErrorContains(t, validateJSON6902Patch(patch("ClusterConfiguration", "op: add\n")), "must be a list of operations")
+	assert.ErrorContains(t, validateJSON6902Patch(patch("ClusterConfiguration", "[]")), "no operations")
+	assert.ErrorContains(t, validateJSON6902Patch(patch("ClusterConfiguration", "- op: append\n  path: /a\n")), `operation 0: unknown op "append"`)
+	assert.ErrorContains(t, validateJSON6902Patch(patch("ClusterConfiguration", "- op: add\n  path: /a\n- op: add\n  path: a\n")), `operation 1: path "a" must start with /`)
+}
+
+// testUnknownValue is how Terraform passes values that are unknown at plan time.
+const testUnknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// TestValidateKubeadmConfigPatches tests that plan errors name the node and patch index
+func TestValidateKubeadmConfigPatches(t *testing.T) {
+	config := func(patches map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"name": "test",
+			"kind_config": []interface{}{
+				map[string]interface{}{
+					"node": []interface{}{
+						map[string]interface{}{"role": "control-plane"},
+						patches,
+					},
+				},
+			},
+		}
+	}
+
+	assert.NoError(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"role":                   "worker",
+		"kubeadm_config_patches": []interface{}{"kind: JoinConfiguration\n"},
+	})))
+
+	err := testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"role":                   "worker",
+		"kubeadm_config_patches": []interface{}{"kind: JoinConfiguration\n", "kind: Join\n"},
+	}))
+	assert.ErrorContains(t, err, "kind_config node 1 kubeadm_config_patches 1: unknown kind")
+
+	err = testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"role": "worker",
+		"kubeadm_config_patches_json6902": []interface{}{
+			map[string]interface{}{
+				"version": "v1beta3",
+				"kind":    "JoinConfiguration",
+				"patch":   "- op: add\n  path: nodeRegistration\n",
+			},
+		},
+	}))
+	assert.ErrorContains(t, err, "kind_config node 1 kubeadm_config_patches_json6902 0: operation 0")
+
+	// Patches rendered from other resources are unknown at plan time
+	assert.NoError(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"role":                   "worker",
+		"kubeadm_config_patches": []interface{}{"kind: JoinConfiguration\n", testUnknownValue},
+		"kubeadm_config_patches_json6902": []interface{}{
+			map[string]interface{}{
+				"version": "v1beta3",
+				"kind":    "JoinConfiguration",
+				"patch":   testUnknownValue,
+			},
+		},
+	})))
+
+	err = testResourceDiff(t, resourceKindCluster(), map[string]interface{}{
+		"name": "test",
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"node": []interface{}{
+					map[string]interface{}{"role": "control-plane", "kubeadm_config_patches": []interface{}{"kind: Init\n"}},
+					map[string]interface{}{"role": "worker", "kubeadm_config_patches": []interface{}{testUnknownValue}},
+				},
+			},
+		},
+	})
+	assert.ErrorContains(t, err, "kind_config node 0 kubeadm_config_patches 0: unknown kind")
+}
+
+// TestJSON6902PatchSchema tests that JSON 6902 patch changes recreate the cluster, as they can't be applied in place
+func TestJSON6902PatchSchema(t *testing.T) {
+	kindConfig := resourceKindCluster().Schema["kind_config"].Elem.(*schema.Resource)
+	node := kindConfig.Schema["node"].Elem.(*schema.Resource)
+	assert.True(t, node.Schema["kubeadm_config_patches_json6902"].ForceNew)
+}
+
+// TestGenerateKindConfig_json6902 tests rendering JSON 6902 patches only when configured
+func TestGenerateKindConfig_json6902(t *testing.T) {
+	operations := "- op: add\n  path: /apiServer/certSANs/-\n  value: kind.example.com\n"
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"node": []interface{}{
+					map[string]interface{}{
+						"role": "control-plane",
+						"kubeadm_config_patches_json6902": []interface{}{
+							map[string]interface{}{
+								"version": "v1beta3",
+								"kind":    "ClusterConfiguration",
+								"patch":   operations,
+							},
+						},
+					},
+					map[string]interface{}{"role": "worker"},
+				},
+			},
+		},
+	})
+
+	nodes := testKindConfig(t, d)["nodes"].([]map[string]interface{})
+
+	assert.Equal(t, []map[string]interface{}{
+		{"group": "kubeadm.k8s.io", "version": "v1beta3", "kind": "ClusterConfiguration", "patch": operations},
+	}, nodes[0]["kubeadmConfigPatchesJSON6902"])
+	assert.NotContains(t, nodes[1], "kubeadmConfigPatchesJSON6902")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// kubeletExtraArgs converts the kubelet block of a node into kubelet flags.
+func kubeletExtraArgs(kubelet map[string]interface{}) map[string]string {
+	args := map[string]string{}
+
+	if maxPods, ok := kubelet["max_pods"].(int); ok && maxPods > 0 {
+		args["max-pods"] = strconv.Itoa(maxPods)
+	}
+	if eviction := joinKubeletMap(kubelet["eviction_hard"], "<"); eviction != "" {
+		args["eviction-hard"] = eviction
+	}
+	if reserved := joinKubeletMap(kubelet["system_reserved"], "="); reserved != "" {
+		args["system-reserved"] = reserved
+	}
+	if gates := joinKubeletMap(kubelet["feature_gates"], "="); gates != "" {
+		args["feature-gates"] = gates
+	}
+	return args
+}
+
+// joinKubeletMap renders a map attribute in the key<sep>value,... form of
+// kubelet flags, sorted by key.
+func joinKubeletMap(v interface{}, sep string) string {
+	m, _ := v.(map[string]interface{})
+	pairs := make([]string, 0, len(m))
+	for key, value := range m {
+		pairs = append(pairs, fmt.Sprintf("%s%s%v", key, sep, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// kubeletPatches renders kubelet flags as node patches. Kind applies both to
+// every node, kubeadm uses the InitConfiguration on the first control-plane
+// node and the JoinConfiguration on all other nodes. Per node kubelet
+// settings cannot go into a KubeletConfiguration, which kubeadm shares
+// across the cluster.
+func kubeletPatches(args map[string]string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	var patches []string
+	for _, kind := range []string{"InitConfiguration", "JoinConfiguration"} {
+		data, _ := yaml.Marshal(map[string]interface{}{
+			"kind": kind,
+			"nodeRegistration": map[string]interface{}{
+				"kubeletExtraArgs": args,
+			},
+		})
+		patches = append(patches, string(data))
+	}
+	return patches
+}
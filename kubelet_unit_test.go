@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKubeletExtraArgs tests converting the kubelet block into kubelet flags
+func TestKubeletExtraArgs(t *testing.T) {
+	args := kubeletExtraArgs(map[string]interface{}{
+		"max_pods": 50,
+		"eviction_hard": map[string]interface{}{
+			"nodefs.available": "10%",
+			"memory.available": "100Mi",
+		},
+		"system_reserved": map[string]interface{}{
+			"memory": "500Mi",
+			"cpu":    "500m",
+		},
+		"feature_gates": map[string]interface{}{
+			"InPlacePodVerticalScaling": true,
+			"GracefulNodeShutdown":      false,
+		},
+	})
+
+	assert.Equal(t, map[string]string{
+		"max-pods":        "50",
+		"eviction-hard":   "memory.available<100Mi,nodefs.available<10%",
+		"system-reserved": "cpu=500m,memory=500Mi",
+		"feature-gates":   "GracefulNodeShutdown=false,InPlacePodVerticalScaling=true",
+	}, args)
+
+	assert.Empty(t, kubeletExtraArgs(map[string]interface{}{"max_pods": 0}))
+}
+
+// TestKubeletPatches tests that the flags are applied on init and join
+func TestKubeletPatches(t *testing.T) {
+	assert.Nil(t, kubeletPatches(nil))
+
+	assert.Equal(t, []string{
+		"kind: InitConfiguration\nnodeRegistration:\n  kubeletExtraArgs:\n    max-pods: \"50\"\n",
+		"kind: JoinConfiguration\nnodeRegistration:\n  kubeletExtraArgs:\n    max-pods: \"50\"\n",
+	}, kubeletPatches(map[string]string{"max-pods": "50"}))
+}
+
+// TestGenerateKindConfig_kubelet tests appending kubelet patches after user supplied patches
+func TestGenerateKindConfig_kubelet(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"node": []interface{}{
+					map[string]interface{}{
+						"role": "control-plane",
+					},
+					map[string]interface{}{
+						"role":                   "worker",
+						"kubeadm_config_patches": []interface{}{"kind: JoinConfiguration\n"},
+						"kubelet": []interface{}{
+							map[string]interface{}{"max_pods": 250},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+	nodes := config["nodes"].([]map[string]interface{})
+	require.Len(t, nodes, 2)
+
+	assert.Nil(t, nodes[0]["kubeadmConfigPatches"])
+	assert.Equal(t, append([]string{"kind: JoinConfiguration\n"}, kubeletPatches(map[string]string{"max-pods": "250"})...),
+		nodes[1]["kubeadmConfigPatches"])
+}
+
+// TestKubeletSchema tests that kubelet changes recreate the cluster, as they can't be applied in place
+func TestKubeletSchema(t *testing.T) {
+	kindConfig := resourceKindCluster().Schema["kind_config"].Elem.(*schema.Resource)
+	node := kindConfig.Schema["node"].Elem.(*schema.Resource)
+	assert.True(t, node.Schema["kubelet"].ForceNew)
+}
@@ -1,47 +1,14 @@
 package main
 
 import (
-	"context"
-	"log"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return &schema.Provider{
-				Schema: map[string]*schema.Schema{
-					"docker_host": {
-						Type:        schema.TypeString,
-						Optional:    true,
-						DefaultFunc: schema.EnvDefaultFunc("DOCKER_HOST", ""),
-						Description: "Docker daemon host",
-					},
-				},
-				ResourcesMap: map[string]*schema.Resource{
-					"kind_cluster": resourceKindCluster(),
-				},
-				ConfigureContextFunc: providerConfigure,
-			}
-		},
+		ProviderFunc: Provider,
 	})
-}
-
-func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	var diags diag.Diagnostics
 
-	config := &ProviderConfig{
-		DockerHost: d.Get("docker_host").(string),
-	}
-
-	log.Printf("[INFO] Initializing Kind provider with Docker host: %s", config.DockerHost)
-
-	return config, diags
+	// Serve returns when Terraform shuts the provider down
+	removeDockerCertMaterial()
 }
-
-type ProviderConfig struct {
-	DockerHost string
-}
\ No newline at end of file
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"gopkg.in/yaml.v2"
+)
+
+// manifestFieldManager is the server-side apply field manager of all objects
+// applied by the provider.
+const manifestFieldManager = "terraform-provider-kind"
+
+// manifestApplyAttempts bounds how often objects that failed to apply are
+// retried, e.g. custom resources applied right after their definition.
+var (
+	manifestApplyAttempts = 5
+	manifestApplyInterval = 5 * time.Second
+)
+
+// manifestObject identifies a Kubernetes object in a manifest.
+type manifestObject struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+}
+
+func (o manifestObject) String() string {
+	if o.Metadata.Namespace != "" {
+		return fmt.Sprintf("%s %s/%s", o.Kind, o.Metadata.Namespace, o.Metadata.Name)
+	}
+	return fmt.Sprintf("%s %s", o.Kind, o.Metadata.Name)
+}
+
+// manifestDocument is a single object of a manifest with its source YAML.
+type manifestDocument struct {
+	Object manifestObject
+	YAML   string
+}
+
+// parseManifest splits multi-document YAML into its objects, skipping empty
+// documents.
+func parseManifest(content string) ([]manifestDocument, error) {
+	var documents []manifestDocument
+	for i, raw := range splitYAMLDocuments(content) {
+		var object manifestObject
+		if err := yaml.Unmarshal([]byte(raw), &object); err != nil {
+			return nil, fmt.Errorf("document %d: %s", i, err)
+		}
+		if object.APIVersion == "" && object.Kind == "" && object.Metadata.Name == "" {
+			continue
+		}
+		if object.APIVersion == "" || object.Kind == "" || object.Metadata.Name == "" {
+			return nil, fmt.Errorf("document %d: apiVersion, kind and metadata.name are required", i)
+		}
+		documents = append(documents, manifestDocument{Object: object, YAML: raw})
+	}
+	return documents, nil
+}
+
+// splitYAMLDocuments splits content on YAML document separators.
+func splitYAMLDocuments(content string) []string {
+	var documents []string
+	var current []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimRight(line, " \t\r") == "---" || strings.HasPrefix(line, "--- ") {
+			documents = append(documents, strings.Join(current, "\n"))
+			current = nil
+			continue
+		}
+		current = append(current, line)
+	}
+	documents = append(documents, strings.Join(current, "\n"))
+
+	var nonEmpty []string
+	for _, document := range documents {
+		if strings.TrimSpace(document) != "" {
+			nonEmpty = append(nonEmpty, document)
+		}
+	}
+	return nonEmpty
+}
+
+// resolveManifestSource returns the content of a manifest entry, which is
+// either inline YAML or the path to a YAML file.
+func resolveManifestSource(entry string) (string, error) {
+	if !isManifestPath(entry) {
+		return entry, nil
+	}
+	info, err := os.Stat(entry)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("file %s not found", entry)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a file", entry)
+	}
+	data, err := os.ReadFile(entry)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// isManifestPath reports whether a manifest entry refers to a file rather than
+// holding inline YAML, which spans several lines or maps keys on one line.
+func isManifestPath(entry string) bool {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || strings.Contains(entry, "\n") {
+		return false
+	}
+	if strings.HasPrefix(entry, "{") || strings.Contains(entry, ": ") {
+		return false
+	}
+	return true
+}
+
+// hashManifests hashes the resolved content of manifest entries, so edits to
+// referenced files are detected as well. No entries hash to an empty string.
+func hashManifests(entries []string) (string, error) {
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	h := sha256.New()
+	for _, entry := range entries {
+		content, err := resolveManifestSource(entry)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%d\n%s\n", len(content), content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeClusterKubeconfig writes the kubeconfig of a cluster to a temporary
+// file for kubectl, reachable from the provider even on remote daemons. The
+// returned function removes the file and closes any tunnel.
+func writeClusterKubeconfig(config *ProviderConfig, clusterName string) (string, func(), error) {
+	kubeconfig, err := getKubeconfig(config, clusterName)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get kubeconfig: %s", err)
+	}
+	kubeconfig, disconnect, err := connectKubeconfig(config, kubeconfig)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to connect to the API server: %s", err)
+	}
+
+	f, err := os.CreateTemp("", "kind-kubeconfig-*")
+	if err != nil {
+		disconnect()
+		return "", nil, err
+	}
+	cleanup := func() {
+		os.Remove(f.Name())
+		disconnect()
+	}
+
+	if _, err := f.WriteString(kubeconfig); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, err
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return f.Name(), cleanup, nil
+}
+
+// applyManifestDocument applies a single object with server-side apply.
+func applyManifestDocument(kubeconfigPath string, document manifestDocument) error {
+	cmd := kubectlCommand(kubeconfigPath, "apply", "--server-side", "--force-conflicts",
+		"--field-manager", manifestFieldManager, "-f", "-")
+	cmd.Stdin = strings.NewReader(document.YAML)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// applyManifestDocuments applies objects in order, retrying objects that
+// failed until they succeed or the attempts are exhausted. Each object that
+// could not be applied is reported as its own diagnostic.
+func applyManifestDocuments(kubeconfigPath string, documents []manifestDocument) diag.Diagnostics {
+	errs := applyManifestObjects(kubeconfigPath, documents)
+
+	var diags diag.Diagnostics
+	for i, document := range documents {
+		if errs[i] != nil {
+			diags = append(diags, applyErrorDiagnostic(document, errs[i]))
+		}
+	}
+	return diags
+}
+
+// applyManifestObjects applies objects in order, retrying objects that failed
+// until they succeed or the attempts are exhausted. The returned errors are
+// indexed like documents and nil for applied objects.
+func applyManifestObjects(kubeconfigPath string, documents []manifestDocument) []error {
+	errs := make([]error, len(documents))
+	pending := make([]int, len(documents))
+	for i := range documents {
+		pending[i] = i
+	}
+
+	for attempt := 1; attempt <= manifestApplyAttempts && len(pending) > 0; attempt++ {
+		if attempt > 1 {
+			time.Sleep(manifestApplyInterval)
+		}
+
+		var failed []int
+		for _, i := range pending {
+			document := documents[i]
+			if errs[i] = applyManifestDocument(kubeconfigPath, document); errs[i] != nil {
+				log.Printf("[DEBUG] Applying %s failed on attempt %d: %s", document.Object, attempt, errs[i])
+				failed = append(failed, i)
+				continue
+			}
+			log.Printf("[INFO] Applied %s", document.Object)
+		}
+		pending = failed
+	}
+	return errs
+}
+
+// applyErrorDiagnostic reports an object that could not be applied.
+func applyErrorDiagnostic(document manifestDocument, err error) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Failed to apply %s", document.Object),
+		Detail:   err.Error(),
+	}
+}
+
+// manifestObjectKey identifies an object across applies.
+func manifestObjectKey(object manifestObject) string {
+	return fmt.Sprintf("%s/%s/%s/%s", object.APIVersion, object.Kind, object.Metadata.Namespace, object.Metadata.Name)
+}
+
+// getManifestObjectUID returns the UID of the live object described by a
+// document, or an empty string when the object does not exist.
+func getManifestObjectUID(kubeconfigPath string, document manifestDocument) (string, error) {
+	cmd := kubectlCommand(kubeconfigPath, "get", "-f", "-", "--ignore-not-found", "-o", "jsonpath={.metadata.uid}")
+	cmd.Stdin = strings.NewReader(document.YAML)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %s", document.Object, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// deleteManifestDocument deletes the live object described by a document,
+// ignoring objects that no longer exist.
+func deleteManifestDocument(kubeconfigPath string, document manifestDocument) error {
+	cmd := kubectlCommand(kubeconfigPath, "delete", "-f", "-", "--ignore-not-found")
+	cmd.Stdin = strings.NewReader(document.YAML)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %s\nOutput: %s", document.Object, err, strings.TrimSpace(string(output)))
+	}
+	log.Printf("[INFO] Deleted %s", document.Object)
+	return nil
+}
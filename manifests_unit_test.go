@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseManifest tests splitting multi-document YAML into objects
+func TestParseManifest(t *testing.T) {
+	content := `# bootstrap objects
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+---
+---
+# only a comment
+--- # trailing comment
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: apps
+data:
+  separator: "---"
+`
+
+	documents, err := parseManifest(content)
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+
+	assert.Equal(t, "Namespace apps", documents[0].Object.String())
+	assert.Equal(t, "ConfigMap apps/settings", documents[1].Object.String())
+	assert.Contains(t, documents[1].YAML, `separator: "---"`)
+}
+
+// TestParseManifest_invalid tests that incomplete objects are rejected
+func TestParseManifest_invalid(t *testing.T) {
+	_, err := parseManifest("kind: ConfigMap\nmetadata:\n  name: missing-api-version\n")
+	assert.Error(t, err)
+
+	_, err = parseManifest("apiVersion: v1\nkind: [\n")
+	assert.Error(t, err)
+}
+
+// TestResolveManifestSource tests reading manifest entries from files or inline YAML
+func TestResolveManifestSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "namespace.yaml")
+	writeTestFile(t, path, "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n")
+
+	content, err := resolveManifestSource(path)
+	require.NoError(t, err)
+	assert.Contains(t, content, "kind: Namespace")
+
+	inline := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: inline\n"
+	content, err = resolveManifestSource(inline)
+	require.NoError(t, err)
+	assert.Equal(t, inline, content)
+}
+
+// TestResolveManifestSource_missing tests that missing files are not read as inline YAML
+func TestResolveManifestSource_missing(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := resolveManifestSource(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = resolveManifestSource(dir)
+	assert.ErrorContains(t, err, "is not a file")
+
+	inline := "{apiVersion: v1, kind: Namespace, metadata: {name: inline}}"
+	content, err := resolveManifestSource(inline)
+	require.NoError(t, err)
+	assert.Equal(t, inline, content)
+}
+
+// TestBootstrapStatusDiagnostics tests that the status of every bootstrap object is reported when an apply failed
+func TestBootstrapStatusDiagnostics(t *testing.T) {
+	documents, err := parseManifest(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: apps
+`)
+	require.NoError(t, err)
+
+	diags := bootstrapStatusDiagnostics(documents, []error{nil, errors.New("forbidden")})
+	require.Len(t, diags, 2)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, "Applied 1 of 2 bootstrap objects", diags[0].Summary)
+	assert.Equal(t, "Namespace apps: applied\nConfigMap apps/settings: failed", diags[0].Detail)
+	assert.Equal(t, diag.Error, diags[1].Severity)
+	assert.Equal(t, "Failed to apply ConfigMap apps/settings", diags[1].Summary)
+	assert.Equal(t, "forbidden", diags[1].Detail)
+
+	// Clean applies emit no diagnostics
+	assert.Empty(t, bootstrapStatusDiagnostics(documents, []error{nil, nil}))
+}
+
+// TestHashManifests tests that file content changes are detected
+func TestHashManifests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "namespace.yaml")
+	writeTestFile(t, path, "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n")
+
+	initial, err := hashManifests([]string{path})
+	require.NoError(t, err)
+
+	writeTestFile(t, path, "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: other\n")
+	changed, err := hashManifests([]string{path})
+	require.NoError(t, err)
+	assert.NotEqual(t, initial, changed)
+
+	empty, err := hashManifests(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "", empty)
+}
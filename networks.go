@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultKindNetwork is the Docker network kind attaches cluster nodes to.
+const defaultKindNetwork = "kind"
+
+// clusterNetwork returns the Docker network the nodes of a cluster are
+// attached to.
+func clusterNetwork(d *schema.ResourceData) string {
+	if network := d.Get("network").(string); network != "" {
+		return network
+	}
+	return defaultKindNetwork
+}
+
+// getNetworkSubnets returns the subnets of a Docker network.
+func getNetworkSubnets(config *ProviderConfig, network string) ([]string, error) {
+	output, err := dockerCommand(config, "network", "inspect", network,
+		"--format", "{{range .IPAM.Config}}{{println .Subnet}}{{end}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect Docker network %s: %s", network, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// firstIPv4Subnet returns the first IPv4 subnet of a list of CIDRs.
+func firstIPv4Subnet(subnets []string) (*net.IPNet, error) {
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q: %s", subnet, err)
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet, nil
+		}
+	}
+	return nil, fmt.Errorf("no IPv4 subnet in %v", subnets)
+}
+
+// carveAddressPool reserves an address range at the end of a subnet, e.g.
+// 172.18.255.200-172.18.255.250 for 172.18.0.0/16. Docker assigns container
+// addresses from the start of the subnet, so the end is unlikely to clash.
+func carveAddressPool(subnet *net.IPNet) (string, error) {
+	ones, bits := subnet.Mask.Size()
+	if bits != 32 {
+		return "", fmt.Errorf("subnet %s is not an IPv4 subnet", subnet)
+	}
+	if bits-ones < 6 {
+		return "", fmt.Errorf("subnet %s is too small for an address pool, at least a /26 is required", subnet)
+	}
+
+	network := subnet.IP.To4()
+	broadcast := make(net.IP, 4)
+	for i := range network {
+		broadcast[i] = network[i] | ^subnet.Mask[i]
+	}
+
+	return fmt.Sprintf("%s-%s", offsetIPv4(broadcast, -55), offsetIPv4(broadcast, -5)), nil
+}
+
+func offsetIPv4(ip net.IP, offset int) net.IP {
+	v := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+	v = uint32(int64(v) + int64(offset))
+	return net.IPv4(byte(v>>24), byte(v>>16), byte(v>>8), byte(v)).To4()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCarveAddressPool tests reserving an address range at the end of a subnet
+func TestCarveAddressPool(t *testing.T) {
+	tests := []struct {
+		subnet      string
+		expected    string
+		expectError bool
+	}{
+		{subnet: "172.18.0.0/16", expected: "172.18.255.200-172.18.255.250"},
+		{subnet: "192.168.10.0/24", expected: "192.168.10.200-192.168.10.250"},
+		{subnet: "10.89.0.0/20", expected: "10.89.15.200-10.89.15.250"},
+		{subnet: "10.0.0.0/26", expected: "10.0.0.8-10.0.0.58"},
+		{subnet: "10.0.0.0/27", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subnet, func(t *testing.T) {
+			subnet, err := firstIPv4Subnet([]string{tt.subnet})
+			require.NoError(t, err)
+
+			pool, err := carveAddressPool(subnet)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, pool)
+		})
+	}
+}
+
+// TestFirstIPv4Subnet tests skipping IPv6 subnets of dual-stack networks
+func TestFirstIPv4Subnet(t *testing.T) {
+	subnet, err := firstIPv4Subnet([]string{"fc00:f853:ccd:e793::/64", "172.18.0.0/16"})
+	require.NoError(t, err)
+	assert.Equal(t, "172.18.0.0/16", subnet.String())
+
+	_, err = firstIPv4Subnet([]string{"fc00:f853:ccd:e793::/64"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed node_images.yaml
+var nodeImagesData []byte
+
+// defaultNodeImage is the node image used when neither node_image nor
+// kubernetes_version is set, pinned to its entry in node_images.yaml.
+const defaultNodeImage = "kindest/node:v1.28.0@sha256:b7a4cad12c197af3ba43202d3efe03246b3f0793f162afb40a33c923952d5b31"
+
+// nodeImageRelease lists the node images published with a kind release,
+// keyed by Kubernetes version.
+type nodeImageRelease struct {
+	Kind   string            `yaml:"kind"`
+	Images map[string]string `yaml:"images"`
+}
+
+// loadNodeImageReleases parses the embedded node image table, ordered from
+// the oldest to the newest kind release.
+func loadNodeImageReleases() ([]nodeImageRelease, error) {
+	var table struct {
+		Releases []nodeImageRelease `yaml:"releases"`
+	}
+	if err := yaml.Unmarshal(nodeImagesData, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse node image table: %s", err)
+	}
+
+	releases := table.Releases
+	sort.SliceStable(releases, func(i, j int) bool {
+		return version.Must(version.NewVersion(releases[i].Kind)).LessThan(version.Must(version.NewVersion(releases[j].Kind)))
+	})
+	return releases, nil
+}
+
+// selectNodeImageRelease returns the release matching kindVersion, or the
+// newest release before it when kind has no entry of its own. An empty
+// kindVersion selects the newest release.
+func selectNodeImageRelease(releases []nodeImageRelease, kindVersion string) (*nodeImageRelease, error) {
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("node image table is empty")
+	}
+	if kindVersion == "" {
+		return &releases[len(releases)-1], nil
+	}
+
+	want, err := version.NewVersion(kindVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kind version %q: %s", kindVersion, err)
+	}
+
+	var selected *nodeImageRelease
+	for i := range releases {
+		if version.Must(version.NewVersion(releases[i].Kind)).GreaterThan(want.Core()) {
+			break
+		}
+		selected = &releases[i]
+	}
+	if selected == nil {
+		return nil, fmt.Errorf("kind %s is older than any release in the node image table (oldest is %s)", kindVersion, releases[0].Kind)
+	}
+	return selected, nil
+}
+
+// resolveNodeImage returns the digest pinned image for a Kubernetes version
+// in a release. A minor version like 1.29 resolves to its newest patch.
+func resolveNodeImage(release *nodeImageRelease, kubernetesVersion string) (string, error) {
+	requested := strings.TrimPrefix(kubernetesVersion, "v")
+	if image, ok := release.Images[requested]; ok {
+		return image, nil
+	}
+
+	var best *version.Version
+	var bestImage string
+	if strings.Count(requested, ".") == 1 {
+		for k8sVersion, image := range release.Images {
+			if !strings.HasPrefix(k8sVersion, requested+".") {
+				continue
+			}
+			v, err := version.NewVersion(k8sVersion)
+			if err != nil {
+				return "", fmt.Errorf("invalid Kubernetes version %q in node image table: %s", k8sVersion, err)
+			}
+			if best == nil || v.GreaterThan(best) {
+				best, bestImage = v, image
+			}
+		}
+	}
+	if best != nil {
+		return bestImage, nil
+	}
+
+	var available []string
+	for k8sVersion := range release.Images {
+		available = append(available, k8sVersion)
+	}
+	sort.Strings(available)
+	return "", fmt.Errorf("no node image for Kubernetes %s in kind %s, available versions: %s",
+		kubernetesVersion, release.Kind, strings.Join(available, ", "))
+}
+
+// getKindVersion returns the version of the kind CLI, e.g. v0.20.0.
+func getKindVersion(config *ProviderConfig) (string, error) {
+	output, err := kindCommand(config, "version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get kind version: %s", err)
+	}
+	return parseKindVersion(string(output))
+}
+
+// parseKindVersion extracts the version from kind version output such as
+// "kind v0.20.0 go1.20.4 linux/amd64".
+func parseKindVersion(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 || fields[0] != "kind" {
+		return "", fmt.Errorf("unexpected kind version output: %q", strings.TrimSpace(output))
+	}
+	return fields[1], nil
+}
+
+// lookupNodeImage resolves a Kubernetes version to a node image for the
+// given kind release, detecting the installed kind CLI when kindVersion is
+// empty.
+func lookupNodeImage(config *ProviderConfig, kindVersion, kubernetesVersion string) (string, error) {
+	if kindVersion == "" {
+		detected, err := getKindVersion(config)
+		if err != nil {
+			return "", err
+		}
+		kindVersion = detected
+	}
+
+	releases, err := loadNodeImageReleases()
+	if err != nil {
+		return "", err
+	}
+	release, err := selectNodeImageRelease(releases, kindVersion)
+	if err != nil {
+		return "", err
+	}
+	return resolveNodeImage(release, kubernetesVersion)
+}
+
+// imageDigest returns the sha256 digest of a digest pinned image reference,
+// or an empty string for tag references.
+func imageDigest(image string) string {
+	i := strings.Index(image, "@sha256:")
+	if i < 0 {
+		return ""
+	}
+	return image[i+1:]
+}
+
+// expectedNodeImage returns the image the nodes of a cluster are verified
+// against: the configured node_image, so changed digests are reported as
+// drift, or the image resolved from kubernetes_version.
+func expectedNodeImage(d *schema.ResourceData) string {
+	if _, ok := d.GetOk("kubernetes_version"); ok {
+		return d.Get("resolved_node_image").(string)
+	}
+	return d.Get("node_image").(string)
+}
+
+// getNodeImageDigests returns the repository digests of the image each node
+// container of a cluster was started from, keyed by node name.
+func getNodeImageDigests(config *ProviderConfig, clusterName string) (map[string][]string, error) {
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	digests := make(map[string][]string, len(nodes))
+	for _, node := range nodes {
+		imageID, err := dockerCommand(config, "inspect", "--format", "{{.Image}}", node).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect node %s: %s", node, err)
+		}
+
+		repoDigests, err := dockerCommand(config, "image", "inspect", "--format", "{{range .RepoDigests}}{{println .}}{{end}}", strings.TrimSpace(string(imageID))).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect image of node %s: %s", node, err)
+		}
+
+		digests[node] = nil
+		for _, repoDigest := range strings.Fields(string(repoDigests)) {
+			if digest := imageDigest(repoDigest); digest != "" {
+				digests[node] = append(digests[node], digest)
+			}
+		}
+	}
+	return digests, nil
+}
+
+// findNodeImageDrift returns the digest each node is running and the nodes
+// whose image does not match the expected digest. Nodes started from an
+// image without repository digests report an empty digest.
+func findNodeImageDrift(nodeDigests map[string][]string, expected string) (map[string]string, []string) {
+	running := make(map[string]string, len(nodeDigests))
+	var drifted []string
+	for node, digests := range nodeDigests {
+		running[node] = ""
+		if len(digests) > 0 {
+			running[node] = digests[0]
+		}
+		for _, digest := range digests {
+			if digest == expected {
+				running[node] = expected
+			}
+		}
+		if running[node] != expected {
+			drifted = append(drifted, node)
+		}
+	}
+	sort.Strings(drifted)
+	return running, drifted
+}
+
+// imageRepository strips the tag and digest from an image reference.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// imagePresentLocally reports whether an image is in the image cache of the
+// Docker daemon.
+func imagePresentLocally(config *ProviderConfig, image string) bool {
+	return dockerCommand(config, "image", "inspect", image).Run() == nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNodeImageTable tests that every embedded node image is pinned to the version it is listed under
+func TestNodeImageTable(t *testing.T) {
+	releases, err := loadNodeImageReleases()
+	require.NoError(t, err)
+	require.NotEmpty(t, releases)
+
+	pinned := regexp.MustCompile(`^kindest/node:v(\d+\.\d+\.\d+)@sha256:[0-9a-f]{64}$`)
+	for _, release := range releases {
+		assert.NotEmpty(t, release.Images, release.Kind)
+		for k8sVersion, image := range release.Images {
+			match := pinned.FindStringSubmatch(image)
+			if assert.NotNil(t, match, "%s: %s", release.Kind, image) {
+				assert.Equal(t, k8sVersion, match[1], "%s: %s", release.Kind, image)
+			}
+		}
+	}
+
+	for i := 1; i < len(releases); i++ {
+		assert.NotEqual(t, releases[i-1].Kind, releases[i].Kind)
+	}
+}
+
+// TestDefaultNodeImage tests that the default node image is an entry of the embedded table
+func TestDefaultNodeImage(t *testing.T) {
+	releases, err := loadNodeImageReleases()
+	require.NoError(t, err)
+
+	var images []string
+	for _, release := range releases {
+		for _, image := range release.Images {
+			images = append(images, image)
+		}
+	}
+	assert.Contains(t, images, defaultNodeImage)
+	assert.NotEmpty(t, imageDigest(defaultNodeImage))
+}
+
+// TestSelectNodeImageRelease tests picking the table entry for the installed kind release
+func TestSelectNodeImageRelease(t *testing.T) {
+	releases := []nodeImageRelease{
+		{Kind: "v0.20.0"},
+		{Kind: "v0.22.0"},
+	}
+
+	tests := []struct {
+		name        string
+		kindVersion string
+		expected    string
+		expectError bool
+	}{
+		{name: "exact release", kindVersion: "v0.20.0", expected: "v0.20.0"},
+		{name: "release without own entry", kindVersion: "v0.21.0", expected: "v0.20.0"},
+		{name: "newer release", kindVersion: "v0.25.0", expected: "v0.22.0"},
+		{name: "pre-release", kindVersion: "v0.22.0-alpha+abc", expected: "v0.22.0"},
+		{name: "detect newest", kindVersion: "", expected: "v0.22.0"},
+		{name: "older than table", kindVersion: "v0.17.0", expectError: true},
+		{name: "invalid version", kindVersion: "latest", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := selectNodeImageRelease(releases, tt.kindVersion)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, release.Kind)
+		})
+	}
+}
+
+// TestResolveNodeImage tests resolving minor and patch Kubernetes versions
+func TestResolveNodeImage(t *testing.T) {
+	release := &nodeImageRelease{
+		Kind: "v0.22.0",
+		Images: map[string]string{
+			"1.29.2":  "kindest/node:v1.29.2@sha256:aaaa",
+			"1.28.7":  "kindest/node:v1.28.7@sha256:bbbb",
+			"1.28.10": "kindest/node:v1.28.10@sha256:cccc",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		version     string
+		expected    string
+		expectError bool
+	}{
+		{name: "exact patch", version: "1.28.7", expected: "kindest/node:v1.28.7@sha256:bbbb"},
+		{name: "v prefix", version: "v1.29.2", expected: "kindest/node:v1.29.2@sha256:aaaa"},
+		{name: "newest patch of minor", version: "1.28", expected: "kindest/node:v1.28.10@sha256:cccc"},
+		{name: "unknown patch", version: "1.29.1", expectError: true},
+		{name: "unknown minor", version: "1.30", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, err := resolveNodeImage(release, tt.version)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, image)
+		})
+	}
+}
+
+// TestParseKindVersion tests parsing of kind version output
+func TestParseKindVersion(t *testing.T) {
+	v, err := parseKindVersion("kind v0.20.0 go1.20.4 linux/amd64\n")
+	require.NoError(t, err)
+	assert.Equal(t, "v0.20.0", v)
+
+	_, err = parseKindVersion("command not found")
+	assert.Error(t, err)
+}
+
+// TestImageDigest tests extracting digests from image references
+func TestImageDigest(t *testing.T) {
+	assert.Equal(t, "sha256:abcd", imageDigest("kindest/node:v1.28.0@sha256:abcd"))
+	assert.Equal(t, "sha256:abcd", imageDigest("kindest/node@sha256:abcd"))
+	assert.Equal(t, "", imageDigest("kindest/node:v1.28.0"))
+}
+
+// TestExpectedNodeImage tests verifying against the configured node_image rather than the image at creation
+func TestExpectedNodeImage(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name":       "test",
+		"node_image": "kindest/node:v1.28.0@sha256:configured",
+	})
+	require.NoError(t, d.Set("resolved_node_image", "kindest/node:v1.28.0@sha256:created"))
+	assert.Equal(t, "kindest/node:v1.28.0@sha256:configured", expectedNodeImage(d))
+
+	d = schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name":               "test",
+		"kubernetes_version": "1.28",
+	})
+	require.NoError(t, d.Set("resolved_node_image", "kindest/node:v1.28.0@sha256:resolved"))
+	assert.Equal(t, "kindest/node:v1.28.0@sha256:resolved", expectedNodeImage(d))
+}
+
+// TestFindNodeImageDrift tests detecting nodes that run a different image than the pinned digest
+func TestFindNodeImageDrift(t *testing.T) {
+	running, drifted := findNodeImageDrift(map[string][]string{
+		"test-control-plane": {"sha256:other", "sha256:pinned"},
+		"test-worker":        {"sha256:repulled"},
+		"test-worker2":       nil,
+	}, "sha256:pinned")
+
+	assert.Equal(t, map[string]string{
+		"test-control-plane": "sha256:pinned",
+		"test-worker":        "sha256:repulled",
+		"test-worker2":       "",
+	}, running)
+	assert.Equal(t, []string{"test-worker", "test-worker2"}, drifted)
+}
+
+// TestImageRepository tests stripping tags and digests from image references
+func TestImageRepository(t *testing.T) {
+	assert.Equal(t, "kindest/node", imageRepository("kindest/node:v1.28.0@sha256:abcd"))
+	assert.Equal(t, "kindest/node", imageRepository("kindest/node:v1.28.0"))
+	assert.Equal(t, "localhost:5000/node", imageRepository("localhost:5000/node"))
+	assert.Equal(t, "localhost:5000/node", imageRepository("localhost:5000/node:dev"))
+}
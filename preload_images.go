@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// isImageArchive reports whether a preload entry refers to an image archive
+// on disk rather than an image in the local Docker image cache.
+func isImageArchive(ref string) bool {
+	info, err := os.Stat(ref)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// preloadImageID identifies the content of a preload entry: the image ID for
+// cached images and the sha256 of the file for archives.
+func preloadImageID(config *ProviderConfig, ref string) (string, error) {
+	if isImageArchive(ref) {
+		f, err := os.Open(ref)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	output, err := dockerCommand(config, "image", "inspect", "--format", "{{.Id}}", ref).Output()
+	if err != nil {
+		return "", fmt.Errorf("image %s not found in the local image cache", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// preloadImageIDs returns the IDs of all preload entries keyed by entry.
+func preloadImageIDs(config *ProviderConfig, refs []string) (map[string]string, error) {
+	ids := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		id, err := preloadImageID(config, ref)
+		if err != nil {
+			return nil, err
+		}
+		ids[ref] = id
+	}
+	return ids, nil
+}
+
+// loadImageIntoCluster loads a cached image or image archive into all nodes
+// of a cluster.
+func loadImageIntoCluster(config *ProviderConfig, clusterName, ref string) error {
+	source := "docker-image"
+	if isImageArchive(ref) {
+		source = "image-archive"
+	}
+
+	log.Printf("[INFO] Loading %s %s into Kind cluster %s", source, ref, clusterName)
+
+	output, err := kindCommand(config, "load", source, ref, "--name", clusterName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to load %s into cluster %s: %s\nOutput: %s", ref, clusterName, err, string(output))
+	}
+	return nil
+}
+
+// preloadImages loads the entries whose ID differs from the previously loaded
+// ID and returns the IDs of all entries.
+func preloadImages(config *ProviderConfig, clusterName string, refs []string, loaded map[string]interface{}) (map[string]string, error) {
+	ids, err := preloadImageIDs(config, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range refs {
+		if previous, ok := loaded[ref]; ok && previous.(string) == ids[ref] {
+			log.Printf("[DEBUG] Image %s already loaded into Kind cluster %s", ref, clusterName)
+			continue
+		}
+		if err := loadImageIntoCluster(config, clusterName, ref); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// setPreloadedImageIDs plans reloading preload entries whose image changed
+// since they were loaded into an existing cluster.
+func setPreloadedImageIDs(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.NewValueKnown("preload_images") {
+		return nil
+	}
+
+	config, _ := m.(*ProviderConfig)
+	ids, err := preloadImageIDs(config, expandStringList(d.Get("preload_images").([]interface{})))
+	if err != nil {
+		// The image may be built later in the same apply
+		log.Printf("[DEBUG] Unable to resolve preload image IDs during plan: %s", err)
+		return d.SetNewComputed("preloaded_image_ids")
+	}
+
+	current := d.Get("preloaded_image_ids").(map[string]interface{})
+	if len(current) == len(ids) {
+		changed := false
+		for ref, id := range ids {
+			if current[ref] != id {
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+	return d.SetNew("preloaded_image_ids", ids)
+}
+
+func expandStringList(list []interface{}) []string {
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPreloadImageID_archive tests that archives are identified by their content
+func TestPreloadImageID_archive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "calico.tar")
+	writeTestFile(t, archive, "image layers")
+
+	assert.True(t, isImageArchive(archive))
+
+	id, err := preloadImageID(&ProviderConfig{}, archive)
+	require.NoError(t, err)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, id)
+
+	writeTestFile(t, archive, "other image layers")
+	changed, err := preloadImageID(&ProviderConfig{}, archive)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, changed)
+}
+
+// TestPreloadImages_unchanged tests that entries are not loaded again when their ID is unchanged
+func TestPreloadImages_unchanged(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "ingress.tar")
+	writeTestFile(t, archive, "image layers")
+
+	id, err := preloadImageID(&ProviderConfig{}, archive)
+	require.NoError(t, err)
+
+	ids, err := preloadImages(&ProviderConfig{}, "test", []string{archive}, map[string]interface{}{archive: id})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{archive: id}, ids)
+}
+
+// TestPreloadImagesSchema tests that preloaded images are reloaded in place
+func TestPreloadImagesSchema(t *testing.T) {
+	resource := resourceKindCluster()
+
+	assert.False(t, resource.Schema["preload_images"].ForceNew)
+	assert.True(t, resource.Schema["preloaded_image_ids"].Computed)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Provider returns the Kind provider with all of its resources registered.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"docker_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("DOCKER_HOST", ""),
+				Description: "Docker daemon host",
+			},
+			"docker_cert_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("DOCKER_CERT_PATH", ""),
+				Description: "Directory holding ca.pem, cert.pem and key.pem to connect to the Docker daemon over TLS",
+			},
+			"docker_tls_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: envBoolDefault("DOCKER_TLS_VERIFY"),
+				Description: "Verify the Docker daemon's certificate against the CA",
+			},
+			"ca_material": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateCACertificate,
+				Description:  "PEM encoded CA certificate of the Docker daemon, used instead of docker_cert_path",
+			},
+			"cert_material": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateCertificate,
+				Description:  "PEM encoded client certificate for the Docker daemon, used instead of docker_cert_path",
+			},
+			"key_material": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validatePrivateKey,
+				Description:  "PEM encoded client private key for the Docker daemon, used instead of docker_cert_path",
+			},
+			"remote_api_server_access": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      apiServerAccessAuto,
+				ValidateFunc: validation.StringInSlice([]string{apiServerAccessAuto, apiServerAccessRewrite, apiServerAccessTunnel}, false),
+				Description:  "How the API servers of clusters on a remote docker_host are reached: rewrite to the remote host, tunnel over SSH, or auto to tunnel for ssh:// hosts and rewrite otherwise. Rewrite requires the API server to listen on a reachable address, e.g. api_server_address 0.0.0.0. Tunnels only serve the provider itself, the endpoint and kubeconfig of clusters keep pointing at 127.0.0.1 of the remote host",
+			},
+			"proxy": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Proxy settings passed to kind for all clusters, read from HTTP_PROXY, HTTPS_PROXY and NO_PROXY when not set",
+				Elem:        &schema.Resource{Schema: proxySchema()},
+			},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"kind_cluster":          resourceKindCluster(),
+			"kind_cluster_logs":     resourceKindClusterLogs(),
+			"kind_node_image_build": resourceKindNodeImageBuild(),
+			"kind_manifest":         resourceKindManifest(),
+			"kind_network":          resourceKindNetwork(),
+			"kind_cluster_peering":  resourceKindClusterPeering(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"kind_node_image": dataSourceKindNodeImage(),
+		},
+		ConfigureContextFunc: providerConfigure,
+	}
+}
+
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := &ProviderConfig{
+		DockerHost: d.Get("docker_host").(string),
+		Proxy:      proxyFromEnv(),
+	}
+	if proxy := d.Get("proxy").([]interface{}); len(proxy) > 0 && proxy[0] != nil {
+		config.Proxy = expandProxy(proxy[0].(map[string]interface{}))
+	}
+
+	tls, err := expandDockerTLS(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.TLS = tls
+
+	remote, err := parseRemoteDocker(config.DockerHost)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	config.Remote = remote
+	config.APIServerAccess, err = apiServerAccess(remote, d.Get("remote_api_server_access").(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if remote != nil {
+		log.Printf("[INFO] Docker host %s is remote, reaching API servers by %s", remote.Host, config.APIServerAccess)
+	}
+
+	log.Printf("[INFO] Initializing Kind provider with Docker host: %s", config.DockerHost)
+	if config.TLS.enabled() {
+		log.Printf("[INFO] Connecting to the Docker daemon over TLS (verify: %t)", config.TLS.Verify)
+	}
+
+	return config, diags
+}
+
+type ProviderConfig struct {
+	DockerHost string
+	Proxy      proxySettings
+	TLS        dockerTLS
+	// Remote is the remote Docker daemon, nil for local daemons
+	Remote          *remoteDocker
+	APIServerAccess string
+}
@@ -14,23 +14,6 @@ var testAccProviderFactories = map[string]func() (*schema.Provider, error){
 	},
 }
 
-func Provider() *schema.Provider {
-	return &schema.Provider{
-		Schema: map[string]*schema.Schema{
-			"docker_host": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("DOCKER_HOST", ""),
-				Description: "Docker daemon host",
-			},
-		},
-		ResourcesMap: map[string]*schema.Resource{
-			"kind_cluster": resourceKindCluster(),
-		},
-		ConfigureContextFunc: providerConfigure,
-	}
-}
-
 func TestProvider(t *testing.T) {
 	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// defaultPodSubnet and defaultServiceSubnet are the IPv4 subnets kind
+	// uses when none are configured.
+	defaultPodSubnet     = "10.244.0.0/16"
+	defaultServiceSubnet = "10.96.0.0/16"
+)
+
+// proxySettings are the proxy settings passed to kind, which forwards them
+// to the nodes.
+type proxySettings struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    []string
+}
+
+// proxySchema is the schema of the proxy blocks of the provider and of
+// kind_cluster.
+func proxySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"http_proxy": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Proxy for HTTP requests",
+		},
+		"https_proxy": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Proxy for HTTPS requests",
+		},
+		"no_proxy": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Hosts and subnets reached without the proxy, in addition to the ones computed for the cluster",
+		},
+	}
+}
+
+// expandProxy converts a proxy block into proxy settings.
+func expandProxy(block map[string]interface{}) proxySettings {
+	return proxySettings{
+		HTTPProxy:  block["http_proxy"].(string),
+		HTTPSProxy: block["https_proxy"].(string),
+		NoProxy:    expandStringList(block["no_proxy"].([]interface{})),
+	}
+}
+
+// proxyFromEnv reads proxy settings from the environment of the provider.
+func proxyFromEnv() proxySettings {
+	getenv := func(name string) string {
+		if value := os.Getenv(strings.ToUpper(name)); value != "" {
+			return value
+		}
+		return os.Getenv(name)
+	}
+
+	settings := proxySettings{
+		HTTPProxy:  getenv("http_proxy"),
+		HTTPSProxy: getenv("https_proxy"),
+	}
+	for _, entry := range strings.Split(getenv("no_proxy"), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			settings.NoProxy = append(settings.NoProxy, entry)
+		}
+	}
+	return settings
+}
+
+func (p proxySettings) enabled() bool {
+	return p.HTTPProxy != "" || p.HTTPSProxy != ""
+}
+
+// clusterProxy merges the proxy block of a cluster into the provider
+// settings. Proxies set on the cluster take precedence, no_proxy entries of
+// both are used.
+func clusterProxy(d *schema.ResourceData, config *ProviderConfig) proxySettings {
+	var settings proxySettings
+	if config != nil {
+		settings = config.Proxy
+	}
+
+	if block := getBlock(d, "proxy"); block != nil {
+		cluster := expandProxy(block)
+		if cluster.HTTPProxy != "" {
+			settings.HTTPProxy = cluster.HTTPProxy
+		}
+		if cluster.HTTPSProxy != "" {
+			settings.HTTPSProxy = cluster.HTTPSProxy
+		}
+		settings.NoProxy = append(append([]string{}, settings.NoProxy...), cluster.NoProxy...)
+	}
+	return settings
+}
+
+// computeNoProxy extends the configured no_proxy entries with everything in
+// the cluster that must not go through the proxy: loopback, the pod and
+// service subnets, in-cluster service domains and the node hostnames.
+func computeNoProxy(clusterName string, kindConfig map[string]interface{}, configured []string) []string {
+	podSubnet, serviceSubnet := defaultPodSubnet, defaultServiceSubnet
+	if networking, ok := kindConfig["networking"].(map[string]interface{}); ok {
+		if subnet, ok := networking["podSubnet"].(string); ok {
+			podSubnet = subnet
+		}
+		if subnet, ok := networking["serviceSubnet"].(string); ok {
+			serviceSubnet = subnet
+		}
+	}
+
+	entries := append([]string{}, configured...)
+	entries = append(entries, "localhost", "127.0.0.1")
+	entries = append(entries, strings.Split(podSubnet, ",")...)
+	entries = append(entries, strings.Split(serviceSubnet, ",")...)
+	entries = append(entries, ".svc", ".cluster.local")
+	entries = append(entries, kindNodeNames(clusterName, kindConfig)...)
+
+	var result []string
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		result = append(result, entry)
+	}
+	return result
+}
+
+// kindNodeNames returns the container names kind gives the nodes of a
+// generated config, e.g. test-control-plane, test-worker and test-worker2.
+func kindNodeNames(clusterName string, kindConfig map[string]interface{}) []string {
+	counts := map[string]int{}
+	var names []string
+
+	nodes, _ := kindConfig["nodes"].([]map[string]interface{})
+	if len(nodes) == 0 {
+		nodes = []map[string]interface{}{{"role": "control-plane"}}
+	}
+	for _, node := range nodes {
+		role, _ := node["role"].(string)
+		counts[role]++
+		name := fmt.Sprintf("%s-%s", clusterName, role)
+		if counts[role] > 1 {
+			name = fmt.Sprintf("%s%d", name, counts[role])
+		}
+		names = append(names, name)
+	}
+
+	// Clusters with several control-plane nodes get a load balancer in front
+	// of the API servers
+	if counts["control-plane"] > 1 {
+		names = append(names, fmt.Sprintf("%s-external-load-balancer", clusterName))
+	}
+	return names
+}
+
+// proxyEnv returns the environment variables kind reads the proxy settings
+// from.
+func proxyEnv(settings proxySettings, noProxy []string) []string {
+	var env []string
+	if settings.HTTPProxy != "" {
+		env = append(env, "HTTP_PROXY="+settings.HTTPProxy)
+	}
+	if settings.HTTPSProxy != "" {
+		env = append(env, "HTTPS_PROXY="+settings.HTTPSProxy)
+	}
+	return append(env, "NO_PROXY="+strings.Join(noProxy, ","))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProxyFromEnv tests reading upper and lower case proxy variables
+func TestProxyFromEnv(t *testing.T) {
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("http_proxy", "http://proxy.example.com:3128")
+	t.Setenv("HTTPS_PROXY", "http://secure-proxy.example.com:3128")
+	t.Setenv("NO_PROXY", "example.com, .internal,")
+
+	assert.Equal(t, proxySettings{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://secure-proxy.example.com:3128",
+		NoProxy:    []string{"example.com", ".internal"},
+	}, proxyFromEnv())
+}
+
+// TestClusterProxy tests that cluster settings take precedence over provider settings
+func TestClusterProxy(t *testing.T) {
+	config := &ProviderConfig{Proxy: proxySettings{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://proxy.example.com:3128",
+		NoProxy:    []string{"example.com"},
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+	})
+	assert.Equal(t, config.Proxy, clusterProxy(d, config))
+	assert.False(t, clusterProxy(d, &ProviderConfig{}).enabled())
+
+	d = schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"proxy": []interface{}{
+			map[string]interface{}{
+				"https_proxy": "http://team-proxy.example.com:8080",
+				"no_proxy":    []interface{}{"registry.internal"},
+			},
+		},
+	})
+	assert.Equal(t, proxySettings{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://team-proxy.example.com:8080",
+		NoProxy:    []string{"example.com", "registry.internal"},
+	}, clusterProxy(d, config))
+	assert.Equal(t, []string{"example.com"}, config.Proxy.NoProxy)
+}
+
+// TestComputeNoProxy tests adding subnets, service domains and node names
+func TestComputeNoProxy(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"kind_config": []interface{}{
+			map[string]interface{}{
+				"node": []interface{}{
+					map[string]interface{}{"role": "control-plane"},
+					map[string]interface{}{"role": "control-plane"},
+					map[string]interface{}{"role": "worker"},
+					map[string]interface{}{"role": "worker"},
+				},
+				"networking": []interface{}{
+					map[string]interface{}{
+						"pod_subnet": "10.10.0.0/16",
+					},
+				},
+			},
+		},
+	})
+
+	noProxy := computeNoProxy("test", testKindConfig(t, d), []string{"example.com", "localhost"})
+
+	assert.Equal(t, []string{
+		"example.com", "localhost", "127.0.0.1",
+		"10.10.0.0/16", "10.96.0.0/16",
+		".svc", ".cluster.local",
+		"test-control-plane", "test-control-plane2", "test-worker", "test-worker2",
+		"test-external-load-balancer",
+	}, noProxy)
+}
+
+// TestComputeNoProxy_defaultNode tests the node name of clusters without configured nodes
+func TestComputeNoProxy_defaultNode(t *testing.T) {
+	noProxy := computeNoProxy("test", map[string]interface{}{}, nil)
+
+	assert.Equal(t, []string{
+		"localhost", "127.0.0.1", "10.244.0.0/16", "10.96.0.0/16", ".svc", ".cluster.local", "test-control-plane",
+	}, noProxy)
+}
+
+// TestProxyEnv tests the variables passed to kind
+func TestProxyEnv(t *testing.T) {
+	assert.Equal(t, []string{
+		"HTTPS_PROXY=http://proxy.example.com:3128",
+		"NO_PROXY=localhost,.svc",
+	}, proxyEnv(proxySettings{HTTPSProxy: "http://proxy.example.com:3128"}, []string{"localhost", ".svc"}))
+}
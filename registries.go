@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// registryConfigPath is the directory containerd reads per registry
+// hosts.toml files from.
+const registryConfigPath = "/etc/containerd/certs.d"
+
+// registryConfigPathPatch switches containerd to per registry host files,
+// which are read on every pull and can be written after the nodes started.
+const registryConfigPathPatch = `[plugins."io.containerd.grpc.v1.cri".registry]
+  config_path = "` + registryConfigPath + `"`
+
+// validateRegistries checks that each registry uses one kind of credentials.
+func validateRegistries(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("registry") {
+		return nil
+	}
+
+	for i, raw := range d.Get("registry").([]interface{}) {
+		if raw == nil {
+			continue
+		}
+		registry := raw.(map[string]interface{})
+		username := registry["username"].(string)
+		password := registry["password"].(string)
+
+		host := registry["host"].(string)
+
+		switch {
+		case (username == "") != (password == ""):
+			return fmt.Errorf("registry %d: username and password must be set together", i)
+		case username != "" && registry["auth"].(string) != "":
+			return fmt.Errorf("registry %d: auth conflicts with username and password", i)
+		case (username != "" || registry["auth"].(string) != "") && tokenAuthRegistry(host):
+			return fmt.Errorf("registry %d: %s authenticates with bearer tokens, credentials are only supported for registries accepting basic auth", i, host)
+		}
+	}
+	return nil
+}
+
+// tokenAuthRegistries are public registries known to authenticate with
+// bearer tokens from a separate realm, which never receives the static
+// Authorization header containerd sends.
+var tokenAuthRegistries = []string{"docker.io", "registry-1.docker.io", "ghcr.io", "quay.io", "gcr.io", "mcr.microsoft.com", "public.ecr.aws"}
+
+// tokenAuthRegistry reports whether a registry host is known to require
+// token authentication.
+func tokenAuthRegistry(host string) bool {
+	hostname := strings.Split(host, ":")[0]
+	if strings.HasSuffix(hostname, ".gcr.io") || strings.HasSuffix(hostname, ".pkg.dev") {
+		return true
+	}
+	return containsString(tokenAuthRegistries, hostname)
+}
+
+// registryServer returns the upstream URL of a registry host.
+func registryServer(host string) string {
+	if host == "docker.io" {
+		return "https://registry-1.docker.io"
+	}
+	return "https://" + host
+}
+
+// registryAuthorization returns the Authorization header value for the
+// credentials of a registry, or an empty string without credentials. The
+// header is sent as is on every request, so only registries accepting basic
+// auth are supported.
+func registryAuthorization(registry map[string]interface{}) string {
+	auth := registry["auth"].(string)
+	if username := registry["username"].(string); username != "" {
+		auth = base64.StdEncoding.EncodeToString([]byte(username + ":" + registry["password"].(string)))
+	}
+	if auth == "" {
+		return ""
+	}
+	return "Basic " + auth
+}
+
+// renderRegistryHosts renders the containerd hosts.toml of a registry. Mirrors
+// are tried in order before the upstream registry. The credentials are only
+// sent to the registry itself, never to its mirrors.
+func renderRegistryHosts(registry map[string]interface{}) string {
+	host := registry["host"].(string)
+	server := registryServer(host)
+	authorization := registryAuthorization(registry)
+
+	endpoints := expandStringList(registry["endpoints"].([]interface{}))
+	if len(endpoints) == 0 {
+		endpoints = []string{server}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "server = %q\n", server)
+	if authorization != "" && !containsString(endpoints, server) {
+		b.WriteString("\n[header]\n")
+		fmt.Fprintf(&b, "  Authorization = [%q]\n", authorization)
+	}
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(&b, "\n[host.%q]\n", endpoint)
+		b.WriteString("  capabilities = [\"pull\", \"resolve\"]\n")
+		if registry["insecure"].(bool) {
+			b.WriteString("  skip_verify = true\n")
+		}
+		if authorization != "" && endpoint == server {
+			fmt.Fprintf(&b, "  [host.%q.header]\n", endpoint)
+			fmt.Fprintf(&b, "    Authorization = [%q]\n", authorization)
+		}
+	}
+	return b.String()
+}
+
+// configureRegistries enables the containerd host files when registries are
+// configured. The files themselves are written into the nodes after
+// creation, so credentials never end up in the Kind config.
+func configureRegistries(d *schema.ResourceData, config map[string]interface{}) {
+	if len(d.Get("registry").([]interface{})) == 0 {
+		return
+	}
+
+	patches, _ := config["containerdConfigPatches"].([]string)
+	config["containerdConfigPatches"] = append(patches, registryConfigPathPatch)
+}
+
+// installRegistries writes the hosts.toml of every registry into all nodes.
+func installRegistries(d *schema.ResourceData, config *ProviderConfig, clusterName string) error {
+	for _, raw := range d.Get("registry").([]interface{}) {
+		registry := raw.(map[string]interface{})
+		host := registry["host"].(string)
+
+		log.Printf("[INFO] Configuring registry %s on Kind cluster %s", host, clusterName)
+
+		hostsPath := path.Join(registryConfigPath, host, "hosts.toml")
+		if err := writeFileOnClusterNodes(config, clusterName, hostsPath, renderRegistryHosts(registry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
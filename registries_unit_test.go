@@ -0,0 +1,142 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRenderRegistryHosts tests rendering mirrors with TLS settings and credentials for the registry only
+func TestRenderRegistryHosts(t *testing.T) {
+	hosts := renderRegistryHosts(map[string]interface{}{
+		"host":      "registry.internal:5000",
+		"endpoints": []interface{}{"https://mirror.internal", "http://cache.internal:5000"},
+		"insecure":  true,
+		"username":  "ci",
+		"password":  "secret",
+		"auth":      "",
+	})
+
+	assert.Equal(t, `server = "https://registry.internal:5000"
+
+[header]
+  Authorization = ["Basic Y2k6c2VjcmV0"]
+
+[host."https://mirror.internal"]
+  capabilities = ["pull", "resolve"]
+  skip_verify = true
+
+[host."http://cache.internal:5000"]
+  capabilities = ["pull", "resolve"]
+  skip_verify = true
+`, hosts)
+}
+
+// TestRenderRegistryHosts_registryEndpoint tests sending credentials to the registry among its mirrors only
+func TestRenderRegistryHosts_registryEndpoint(t *testing.T) {
+	hosts := renderRegistryHosts(map[string]interface{}{
+		"host":      "registry.internal",
+		"endpoints": []interface{}{"https://mirror.internal", "https://registry.internal"},
+		"insecure":  false,
+		"username":  "ci",
+		"password":  "secret",
+		"auth":      "",
+	})
+
+	assert.Equal(t, `server = "https://registry.internal"
+
+[host."https://mirror.internal"]
+  capabilities = ["pull", "resolve"]
+
+[host."https://registry.internal"]
+  capabilities = ["pull", "resolve"]
+  [host."https://registry.internal".header]
+    Authorization = ["Basic Y2k6c2VjcmV0"]
+`, hosts)
+}
+
+// TestRenderRegistryHosts_privateRegistry tests a registry without mirrors using a Docker auth value
+func TestRenderRegistryHosts_privateRegistry(t *testing.T) {
+	hosts := renderRegistryHosts(map[string]interface{}{
+		"host":      "registry.internal:5000",
+		"endpoints": []interface{}{},
+		"insecure":  false,
+		"username":  "",
+		"password":  "",
+		"auth":      "Y2k6c2VjcmV0",
+	})
+
+	assert.Equal(t, `server = "https://registry.internal:5000"
+
+[host."https://registry.internal:5000"]
+  capabilities = ["pull", "resolve"]
+  [host."https://registry.internal:5000".header]
+    Authorization = ["Basic Y2k6c2VjcmV0"]
+`, hosts)
+}
+
+// TestValidateRegistries tests that each registry uses one kind of credentials
+func TestValidateRegistries(t *testing.T) {
+	config := func(registry map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"name":     "test",
+			"registry": []interface{}{registry},
+		}
+	}
+
+	assert.NoError(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"host": "registry.internal", "username": "ci", "password": "secret",
+	})))
+	assert.ErrorContains(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"host": "registry.internal", "username": "ci",
+	})), "registry 0: username and password must be set together")
+	assert.ErrorContains(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"host": "registry.internal", "username": "ci", "password": "secret", "auth": "Y2k6c2VjcmV0",
+	})), "registry 0: auth conflicts with username and password")
+
+	// Mirrors of token auth registries need no credentials
+	assert.NoError(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"host": "docker.io", "endpoints": []interface{}{"https://mirror.internal"},
+	})))
+	assert.ErrorContains(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"host": "docker.io", "username": "ci", "password": "secret",
+	})), "registry 0: docker.io authenticates with bearer tokens")
+	assert.ErrorContains(t, testResourceDiff(t, resourceKindCluster(), config(map[string]interface{}{
+		"host": "europe-docker.pkg.dev", "auth": "Y2k6c2VjcmV0",
+	})), "registry 0: europe-docker.pkg.dev authenticates with bearer tokens")
+}
+
+// TestTokenAuthRegistry tests detecting registries that don't accept basic auth
+func TestTokenAuthRegistry(t *testing.T) {
+	for _, host := range []string{"docker.io", "ghcr.io", "quay.io", "us.gcr.io", "europe-docker.pkg.dev"} {
+		assert.True(t, tokenAuthRegistry(host), host)
+	}
+	for _, host := range []string{"registry.internal", "registry.internal:5000", "localhost:5000"} {
+		assert.False(t, tokenAuthRegistry(host), host)
+	}
+}
+
+// TestConfigureRegistries tests that credentials stay out of the Kind config
+func TestConfigureRegistries(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+		"registry": []interface{}{
+			map[string]interface{}{
+				"host":     "registry.internal:5000",
+				"username": "ci",
+				"password": "secret",
+			},
+		},
+	})
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []string{registryConfigPathPatch}, config["containerdConfigPatches"])
+	assert.NotContains(t, registryConfigPathPatch, "secret")
+
+	d = schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+	})
+	assert.NotContains(t, testKindConfig(t, d), "containerdConfigPatches")
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// apiServerAccessAuto tunnels over SSH daemons and rewrites for others.
+	apiServerAccessAuto = "auto"
+	// apiServerAccessRewrite reaches the API server on the remote host.
+	apiServerAccessRewrite = "rewrite"
+	// apiServerAccessTunnel forwards a local port over SSH to the API server.
+	apiServerAccessTunnel = "tunnel"
+)
+
+// tunnelReadyTimeout bounds how long an SSH port-forward may take to accept
+// connections.
+var tunnelReadyTimeout = 15 * time.Second
+
+// remoteDocker is a Docker daemon on another machine, on which kind binds
+// the API server ports.
+type remoteDocker struct {
+	Scheme string
+	User   string
+	Host   string
+	Port   string
+}
+
+// parseRemoteDocker returns the remote daemon addressed by a DOCKER_HOST
+// value, or nil for local daemons.
+func parseRemoteDocker(dockerHost string) (*remoteDocker, error) {
+	if dockerHost == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(dockerHost)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker_host %q: %s", dockerHost, err)
+	}
+
+	switch u.Scheme {
+	case "ssh":
+	case "tcp", "http", "https":
+		if ip := net.ParseIP(u.Hostname()); u.Hostname() == "localhost" || (ip != nil && ip.IsLoopback()) {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	return &remoteDocker{
+		Scheme: u.Scheme,
+		User:   u.User.Username(),
+		Host:   u.Hostname(),
+		Port:   u.Port(),
+	}, nil
+}
+
+// apiServerAccess resolves how the provider reaches API servers on a remote
+// daemon. Local daemons need no special access and return an empty mode.
+func apiServerAccess(remote *remoteDocker, mode string) (string, error) {
+	if remote == nil {
+		return "", nil
+	}
+	if mode == "" || mode == apiServerAccessAuto {
+		if remote.Scheme == "ssh" {
+			return apiServerAccessTunnel, nil
+		}
+		return apiServerAccessRewrite, nil
+	}
+	if mode == apiServerAccessTunnel && remote.Scheme != "ssh" {
+		return "", fmt.Errorf("remote_api_server_access = %q requires an ssh:// docker_host", mode)
+	}
+	return mode, nil
+}
+
+// remoteAPIServerHost returns the host API servers are reached at when the
+// provider rewrites kubeconfigs for a remote daemon.
+func remoteAPIServerHost(config *ProviderConfig) string {
+	if config == nil || config.Remote == nil || config.APIServerAccess != apiServerAccessRewrite {
+		return ""
+	}
+	return config.Remote.Host
+}
+
+// remoteHostMountedSettings are settings whose files are written on the
+// provider machine and mounted into the nodes, which a remote daemon resolves
+// on its own filesystem instead.
+var remoteHostMountedSettings = []string{"audit", "encryption_at_rest", "trusted_ca_certificates", "oidc.0.ca_file"}
+
+// validateRemoteDocker rejects settings a remote daemon can't serve at plan
+// time: files mounted from the provider machine, and rewritten API server
+// addresses the API server doesn't listen on.
+func validateRemoteDocker(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config, _ := m.(*ProviderConfig)
+	if config == nil || config.Remote == nil {
+		return nil
+	}
+
+	for _, key := range remoteHostMountedSettings {
+		if _, ok := d.GetOk(key); ok {
+			return fmt.Errorf("%s is not supported with the remote docker_host %s, its files are mounted from the provider machine", key, config.Remote.Host)
+		}
+	}
+
+	if config.APIServerAccess != apiServerAccessRewrite {
+		return nil
+	}
+	if !d.NewValueKnown("api_server_external_host") || !d.NewValueKnown("kind_config.0.networking.0.api_server_address") {
+		return nil
+	}
+	if d.Get("api_server_external_host").(string) != "" {
+		return nil
+	}
+	if ip := net.ParseIP(d.Get("kind_config.0.networking.0.api_server_address").(string)); ip != nil && !ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("the API server of clusters on the remote docker_host %s is reached at %s, set kind_config networking api_server_address, e.g. to 0.0.0.0, or api_server_external_host so it listens on a reachable address", config.Remote.Host, config.Remote.Host)
+}
+
+// connectKubeconfig makes a kubeconfig usable from the provider. For remote
+// daemons the server is rewritten to the remote host, or to a local SSH
+// port-forward that is closed by the returned function.
+func connectKubeconfig(config *ProviderConfig, kubeconfig string) (string, func(), error) {
+	noop := func() {}
+	if config == nil || config.Remote == nil {
+		return kubeconfig, noop, nil
+	}
+
+	data, err := parseKubeconfig(kubeconfig)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch config.APIServerAccess {
+	case apiServerAccessRewrite:
+		return rewriteKubeconfigServer(kubeconfig, data.Endpoint, config.Remote.Host), noop, nil
+	case apiServerAccessTunnel:
+		u, err := url.Parse(data.Endpoint)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid API server endpoint %q: %s", data.Endpoint, err)
+		}
+		localPort, closeTunnel, err := openSSHTunnel(config.Remote, u.Host)
+		if err != nil {
+			return "", nil, err
+		}
+		local := fmt.Sprintf("https://127.0.0.1:%d", localPort)
+		return replaceKubeconfigServer(kubeconfig, data.Endpoint, local), closeTunnel, nil
+	}
+	return kubeconfig, noop, nil
+}
+
+// sshArgs returns the ssh arguments addressing a remote daemon.
+func (r *remoteDocker) sshArgs() []string {
+	var args []string
+	if r.Port != "" {
+		args = append(args, "-p", r.Port)
+	}
+	destination := r.Host
+	if r.User != "" {
+		destination = r.User + "@" + r.Host
+	}
+	return append(args, destination)
+}
+
+// openSSHTunnel forwards a free local port to an address on the remote
+// machine and waits until the forward accepts connections. It fails as soon
+// as ssh exits, e.g. when the port was taken between choosing and binding it.
+// The returned function stops the tunnel.
+func openSSHTunnel(remote *remoteDocker, remoteAddress string) (int, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to find a free local port: %s", err)
+	}
+	localPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	args := append([]string{"-N",
+		"-o", "BatchMode=yes",
+		"-o", "ExitOnForwardFailure=yes",
+		"-L", fmt.Sprintf("127.0.0.1:%d:%s", localPort, remoteAddress),
+	}, remote.sshArgs()...)
+	cmd := exec.Command("ssh", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return 0, nil, fmt.Errorf("failed to start SSH tunnel: %s", err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	closeTunnel := func() {
+		cmd.Process.Kill()
+		<-exited
+	}
+
+	log.Printf("[DEBUG] Forwarding 127.0.0.1:%d to %s on %s", localPort, remoteAddress, remote.Host)
+
+	local := net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort))
+	deadline := time.Now().Add(tunnelReadyTimeout)
+	for {
+		select {
+		case err := <-exited:
+			return 0, nil, fmt.Errorf("SSH tunnel to %s on %s exited: %v\nOutput: %s", remoteAddress, remote.Host, err, strings.TrimSpace(stderr.String()))
+		default:
+		}
+
+		conn, err := net.DialTimeout("tcp", local, time.Second)
+		if err == nil {
+			conn.Close()
+			return localPort, closeTunnel, nil
+		}
+		if time.Now().After(deadline) {
+			closeTunnel()
+			return 0, nil, fmt.Errorf("SSH tunnel to %s on %s did not become ready: %s", remoteAddress, remote.Host, err)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRemoteDocker tests detecting remote Docker daemons from docker_host
+func TestParseRemoteDocker(t *testing.T) {
+	for _, dockerHost := range []string{
+		"",
+		"unix:///var/run/docker.sock",
+		"npipe:////./pipe/docker_engine",
+		"tcp://127.0.0.1:2375",
+		"tcp://localhost:2376",
+		"tcp://[::1]:2375",
+	} {
+		remote, err := parseRemoteDocker(dockerHost)
+		require.NoError(t, err, dockerHost)
+		assert.Nil(t, remote, dockerHost)
+	}
+
+	remote, err := parseRemoteDocker("ssh://builder@docker-host.example.com:2222")
+	require.NoError(t, err)
+	assert.Equal(t, &remoteDocker{Scheme: "ssh", User: "builder", Host: "docker-host.example.com", Port: "2222"}, remote)
+	assert.Equal(t, []string{"-p", "2222", "builder@docker-host.example.com"}, remote.sshArgs())
+
+	remote, err = parseRemoteDocker("tcp://192.168.1.20:2376")
+	require.NoError(t, err)
+	assert.Equal(t, &remoteDocker{Scheme: "tcp", Host: "192.168.1.20", Port: "2376"}, remote)
+
+	remote, err = parseRemoteDocker("ssh://docker-host.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker-host.example.com"}, remote.sshArgs())
+
+	_, err = parseRemoteDocker("tcp://docker-host.example.com:port")
+	assert.ErrorContains(t, err, "invalid docker_host")
+}
+
+// TestAPIServerAccess tests resolving how API servers on remote daemons are reached
+func TestAPIServerAccess(t *testing.T) {
+	ssh := &remoteDocker{Scheme: "ssh", Host: "docker-host.example.com"}
+	tcp := &remoteDocker{Scheme: "tcp", Host: "docker-host.example.com"}
+
+	mode, err := apiServerAccess(nil, apiServerAccessTunnel)
+	require.NoError(t, err)
+	assert.Equal(t, "", mode)
+
+	mode, err = apiServerAccess(ssh, apiServerAccessAuto)
+	require.NoError(t, err)
+	assert.Equal(t, apiServerAccessTunnel, mode)
+
+	mode, err = apiServerAccess(tcp, apiServerAccessAuto)
+	require.NoError(t, err)
+	assert.Equal(t, apiServerAccessRewrite, mode)
+
+	mode, err = apiServerAccess(ssh, apiServerAccessRewrite)
+	require.NoError(t, err)
+	assert.Equal(t, apiServerAccessRewrite, mode)
+
+	_, err = apiServerAccess(tcp, apiServerAccessTunnel)
+	assert.ErrorContains(t, err, "requires an ssh:// docker_host")
+}
+
+// TestConnectKubeconfig tests kubeconfigs for local daemons and rewritten remote daemons
+func TestConnectKubeconfig(t *testing.T) {
+	kubeconfig := "clusters:\n- cluster:\n    server: https://127.0.0.1:40123\n  name: kind-test\n"
+
+	connected, cleanup, err := connectKubeconfig(&ProviderConfig{}, kubeconfig)
+	require.NoError(t, err)
+	cleanup()
+	assert.Equal(t, kubeconfig, connected)
+
+	config := &ProviderConfig{
+		Remote:          &remoteDocker{Scheme: "tcp", Host: "docker-host.example.com"},
+		APIServerAccess: apiServerAccessRewrite,
+	}
+	connected, cleanup, err = connectKubeconfig(config, kubeconfig)
+	require.NoError(t, err)
+	cleanup()
+	assert.Equal(t, "clusters:\n- cluster:\n    server: https://docker-host.example.com:40123\n  name: kind-test\n", connected)
+	assert.Equal(t, "docker-host.example.com", remoteAPIServerHost(config))
+
+	config.APIServerAccess = apiServerAccessTunnel
+	assert.Equal(t, "", remoteAPIServerHost(config))
+}
+
+// TestConfigureExternalAccess_remoteHost tests that the remote daemon's host is added to the certificate SANs
+// without changing the address the API server listens on
+func TestConfigureExternalAccess_remoteHost(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+	})
+	require.NoError(t, d.Set("api_server_remote_host", "docker-host.example.com"))
+
+	config := testKindConfig(t, d)
+
+	assert.Equal(t, []string{`kind: ClusterConfiguration
+apiServer:
+  certSANs:
+  - docker-host.example.com
+`}, config["kubeadmConfigPatches"])
+	assert.Nil(t, config["networking"])
+}
+
+// TestOpenSSHTunnel_exited tests failing as soon as ssh exits instead of waiting for the tunnel timeout
+func TestOpenSSHTunnel_exited(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh is a shell script")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho 'bind [127.0.0.1]: Address already in use' >&2\nexit 255\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0755))
+	t.Setenv("PATH", dir)
+
+	start := time.Now()
+	_, _, err := openSSHTunnel(&remoteDocker{Scheme: "ssh", Host: "docker-host.example.com"}, "127.0.0.1:40123")
+	assert.ErrorContains(t, err, "Address already in use")
+	assert.Less(t, time.Since(start), tunnelReadyTimeout)
+}
+
+// TestValidateRemoteDocker tests rejecting settings a remote daemon can't serve at plan time
+func TestValidateRemoteDocker(t *testing.T) {
+	diff := func(config *ProviderConfig, raw map[string]interface{}) error {
+		_, err := resourceKindCluster().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), config)
+		return err
+	}
+	rewrite := &ProviderConfig{
+		Remote:          &remoteDocker{Scheme: "tcp", Host: "docker-host.example.com"},
+		APIServerAccess: apiServerAccessRewrite,
+	}
+	tunnel := &ProviderConfig{
+		Remote:          &remoteDocker{Scheme: "ssh", Host: "docker-host.example.com"},
+		APIServerAccess: apiServerAccessTunnel,
+	}
+	networking := func(address string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": "test",
+			"kind_config": []interface{}{
+				map[string]interface{}{
+					"networking": []interface{}{
+						map[string]interface{}{"api_server_address": address},
+					},
+				},
+			},
+		}
+	}
+
+	// Rewritten addresses must be reachable on the remote host
+	assert.NoError(t, diff(&ProviderConfig{}, map[string]interface{}{"name": "test"}))
+	assert.NoError(t, diff(tunnel, map[string]interface{}{"name": "test"}))
+	assert.ErrorContains(t, diff(rewrite, map[string]interface{}{"name": "test"}), "set kind_config networking api_server_address")
+	assert.ErrorContains(t, diff(rewrite, networking("127.0.0.1")), "set kind_config networking api_server_address")
+	assert.NoError(t, diff(rewrite, networking("0.0.0.0")))
+	assert.NoError(t, diff(rewrite, map[string]interface{}{
+		"name":                     "test",
+		"api_server_external_host": "docker-host.example.com",
+	}))
+
+	// Files written on the provider machine can't be mounted by remote daemons
+	err := diff(tunnel, map[string]interface{}{
+		"name":                    "test",
+		"trusted_ca_certificates": []interface{}{testCACertificate(t)},
+	})
+	assert.ErrorContains(t, err, "trusted_ca_certificates is not supported with the remote docker_host docker-host.example.com")
+	err = diff(tunnel, testEncryptionConfig())
+	assert.ErrorContains(t, err, "encryption_at_rest is not supported")
+	err = diff(tunnel, map[string]interface{}{
+		"name": "test",
+		"oidc": []interface{}{
+			map[string]interface{}{
+				"issuer_url": "https://issuer.example.com",
+				"client_id":  "kubernetes",
+				"ca_file":    "/etc/oidc/ca.crt",
+			},
+		},
+	})
+	assert.ErrorContains(t, err, "oidc.0.ca_file is not supported")
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"gopkg.in/yaml.v2"
 )
 
@@ -24,8 +26,19 @@ func resourceKindCluster() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: customdiff.All(
+			validateNodeImageDigest,
+			setPreloadedImageIDs,
+			setBootstrapManifestsHash,
+			validateEncryptionAtRest,
+			validateKubeadmConfigPatches,
+			validateRegistries,
+			validateRemoteDocker,
+		),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
@@ -36,28 +49,413 @@ func resourceKindCluster() *schema.Resource {
 				ForceNew:    true,
 				Description: "The name of the Kind cluster",
 			},
+			"network": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Docker network the cluster nodes are attached to, created by kind if missing. Defaults to the kind network",
+			},
 			"node_image": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "kindest/node:v1.28.0",
+				Default:     defaultNodeImage,
 				Description: "Docker image to use for cluster nodes",
 			},
+			"kubernetes_version": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"node_image"},
+				ValidateFunc:  validation.StringMatch(regexp.MustCompile(`^v?\d+\.\d+(\.\d+)?$`), "must be a Kubernetes version such as 1.29 or 1.29.2"),
+				Description:   "Kubernetes version, e.g. 1.29 or 1.29.2, resolved to the digest pinned node image for the installed kind release",
+			},
+			"resolved_node_image": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Node image the cluster was created with",
+			},
+			"require_node_image_digest": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Require node_image to be pinned with an @sha256: digest",
+			},
+			"node_image_digests": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Image digest each node container is running, keyed by node name. Only tracked for digest pinned node images",
+			},
 			"wait_for_ready": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     true,
 				Description: "Wait for the cluster to be ready",
 			},
+			"running": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the cluster node containers are running. Set to false to stop the cluster without destroying it",
+			},
+			"preload_images": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Images from the local image cache or paths to image archives, loaded into all nodes before waiting for readiness",
+			},
+			"preloaded_image_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the preloaded images keyed by preload_images entry. Changed images are loaded again in place",
+			},
+			"bootstrap_manifests": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "YAML documents or paths to YAML files applied with server-side apply once the cluster is ready",
+			},
+			"bootstrap_manifests_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hash of the bootstrap manifest content. The manifests are applied again when it changes",
+			},
+			"ingress": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Install an ingress controller on the first control-plane node and map its ports to the host",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"controller": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "nginx",
+							ValidateFunc: validation.StringInSlice([]string{"nginx"}, false),
+							Description:  "Ingress controller to install",
+						},
+						"http_port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      80,
+							ValidateFunc: validation.IsPortNumber,
+							Description:  "Host port mapped to the controller's HTTP port",
+						},
+						"https_port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      443,
+							ValidateFunc: validation.IsPortNumber,
+							Description:  "Host port mapped to the controller's HTTPS port",
+						},
+					},
+				},
+			},
+			"load_balancer": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Install MetalLB so LoadBalancer services get addresses from the cluster's Docker network",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address_range": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "Address range of the pool, e.g. 172.18.255.200-172.18.255.250. Carved from the Docker network subnet when not set",
+						},
+					},
+				},
+			},
+			"oidc": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Configure the API server to authenticate users with OpenID Connect tokens",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"issuer_url": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsURLWithHTTPS,
+							Description:  "URL of the OpenID issuer, only https is accepted",
+						},
+						"client_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Client ID all tokens must be issued for",
+						},
+						"username_claim": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "JWT claim to use as the user name, the API server defaults to sub",
+						},
+						"username_prefix": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Prefix prepended to user names",
+						},
+						"groups_claim": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "JWT claim to use as the user's groups",
+						},
+						"groups_prefix": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Prefix prepended to group names",
+						},
+						"ca_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path on the host to the CA certificate that signed the issuer's certificate, mounted into all control-plane nodes",
+						},
+					},
+				},
+			},
+			"audit": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Enable audit logging on the API server with logs written to the host",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"policy": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateAuditPolicy,
+							Description:  "Audit policy YAML, an audit.k8s.io Policy",
+						},
+						"max_age": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      7,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Days to keep rotated audit logs",
+						},
+						"max_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      100,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Size in megabytes at which the audit log is rotated",
+						},
+						"log_dir": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Host directory for the audit logs. Defaults to ~/.terraform-provider-kind/audit-logs/<name>. The logs are kept when the cluster is deleted",
+						},
+					},
+				},
+			},
+			"encryption_at_rest": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Encrypt resources in etcd with an EncryptionConfiguration, verified after the cluster is ready",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resources": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Resources to encrypt, defaults to secrets",
+						},
+						"provider": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Description: "Encryption providers in order, the first one encrypts new data",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice([]string{"aescbc", "aesgcm", "secretbox", "kms", "identity"}, false),
+										Description:  "Provider type: aescbc, aesgcm, secretbox, kms or identity",
+									},
+									"key": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "Keys of aescbc, aesgcm and secretbox providers",
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"name": {
+													Type:        schema.TypeString,
+													Required:    true,
+													Description: "Name of the key",
+												},
+												"secret": {
+													Type:         schema.TypeString,
+													Required:     true,
+													Sensitive:    true,
+													ValidateFunc: validation.StringIsBase64,
+													Description:  "Base64 encoded key",
+												},
+											},
+										},
+									},
+									"kms_name": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "Name of the KMS plugin",
+									},
+									"kms_endpoint": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "Endpoint of the KMS plugin on the control-plane nodes, e.g. unix:///var/run/kms.sock",
+									},
+									"kms_api_version": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      "v2",
+										ValidateFunc: validation.StringInSlice([]string{"v1", "v2"}, false),
+										Description:  "KMS API version",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"proxy": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Proxy settings of the nodes, taking precedence over the provider proxy settings",
+				Elem:        &schema.Resource{Schema: proxySchema()},
+			},
+			"effective_no_proxy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "NO_PROXY passed to the nodes, including the computed cluster subnets, service domains and node hostnames",
+			},
+			"registry": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Registry mirrors and credentials, written into the containerd configuration of all nodes",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Registry host images are referenced by, e.g. docker.io or registry.internal:5000",
+						},
+						"endpoints": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.IsURLWithScheme([]string{"http", "https"})},
+							Description: "Mirror URLs tried in order before the registry itself",
+						},
+						"insecure": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Skip TLS verification of the registry and its mirrors",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Username for the registry, sent as basic auth. Registries using token auth such as docker.io are not supported",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Password for the registry",
+						},
+						"auth": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Sensitive:    true,
+							ValidateFunc: validation.StringIsBase64,
+							Description:  "Base64 encoded username:password as found in Docker config files, instead of username and password. Sent as basic auth like username and password",
+						},
+					},
+				},
+			},
+			"trusted_ca_certificates": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateCACertificate},
+				Description: "PEM encoded CA certificates added to the trust store of all nodes, e.g. for registries behind TLS interception",
+			},
+			"api_server_extra_args": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Extra flags of the API server without leading dashes, taking precedence over flags generated from other settings",
+			},
+			"controller_manager_extra_args": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Extra flags of the controller manager without leading dashes",
+			},
+			"scheduler_extra_args": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Extra flags of the scheduler without leading dashes",
+			},
+			"etcd_extra_args": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Extra flags of etcd without leading dashes",
+			},
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Host path of the audit log of the first control-plane node, other control-plane nodes log to sibling directories",
+			},
 			"kubeconfig_path": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Path to the kubeconfig file",
 			},
+			"api_server_cert_sans": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Extra host names and addresses the API server certificate is valid for",
+			},
+			"api_server_external_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Host the API server is reached at from outside the Docker host, e.g. the host of a remote docker_host. Used in endpoint and kubeconfig, added to the certificate SANs and makes the API server listen on all addresses unless api_server_address is set",
+			},
+			"api_server_remote_host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Remote docker_host the API server is reached at when the provider rewrites endpoints for remote daemons, added to the certificate SANs. Empty in tunnel mode, where endpoint and kubeconfig point at 127.0.0.1 of the remote host and are only reachable there or through your own tunnel",
+			},
 			"endpoint": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Kubernetes API server endpoint",
 			},
+			"kubeconfig": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Kubeconfig of the cluster, pointing to api_server_external_host or api_server_remote_host when set",
+			},
 			"cluster_ca_certificate": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -128,6 +526,37 @@ func resourceKindCluster() *schema.Resource {
 										Optional: true,
 										Elem:     &schema.Schema{Type: schema.TypeString},
 									},
+									"kubeadm_config_patches_json6902": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										ForceNew:    true,
+										Description: "JSON 6902 patches of the kubeadm configuration, targeting a document by group, version and kind",
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"group": {
+													Type:        schema.TypeString,
+													Optional:    true,
+													Default:     "kubeadm.k8s.io",
+													Description: "API group of the patched document",
+												},
+												"version": {
+													Type:        schema.TypeString,
+													Required:    true,
+													Description: "API version of the patched document, e.g. v1beta3",
+												},
+												"kind": {
+													Type:        schema.TypeString,
+													Required:    true,
+													Description: "Kind of the patched document, e.g. ClusterConfiguration",
+												},
+												"patch": {
+													Type:        schema.TypeString,
+													Required:    true,
+													Description: "List of JSON 6902 operations as YAML or JSON",
+												},
+											},
+										},
+									},
 									"extra_mounts": {
 										Type:     schema.TypeList,
 										Optional: true,
@@ -165,12 +594,127 @@ func resourceKindCluster() *schema.Resource {
 											},
 										},
 									},
+									"kubelet": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										ForceNew:    true,
+										MaxItems:    1,
+										Description: "Kubelet settings of the node, rendered into kubeletExtraArgs of the Init and Join configurations",
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"max_pods": {
+													Type:         schema.TypeInt,
+													Optional:     true,
+													ValidateFunc: validation.IntAtLeast(1),
+													Description:  "Maximum number of pods on the node",
+												},
+												"eviction_hard": {
+													Type:        schema.TypeMap,
+													Optional:    true,
+													Elem:        &schema.Schema{Type: schema.TypeString},
+													Description: "Hard eviction thresholds by signal, e.g. memory.available = \"100Mi\"",
+												},
+												"system_reserved": {
+													Type:        schema.TypeMap,
+													Optional:    true,
+													Elem:        &schema.Schema{Type: schema.TypeString},
+													Description: "Resources reserved for system daemons, e.g. cpu = \"500m\"",
+												},
+												"feature_gates": {
+													Type:        schema.TypeMap,
+													Optional:    true,
+													Elem:        &schema.Schema{Type: schema.TypeBool},
+													Description: "Kubelet feature gates",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						"networking": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: "Cluster networking settings",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"disable_default_cni": {
+										Type:        schema.TypeBool,
+										Optional:    true,
+										ForceNew:    true,
+										Default:     false,
+										Description: "Do not install the default CNI (kindnet)",
+									},
+									"pod_subnet": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ForceNew:     true,
+										ValidateFunc: validation.IsCIDR,
+										Description:  "Subnet pod IPs are allocated from",
+									},
+									"service_subnet": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ForceNew:     true,
+										ValidateFunc: validation.IsCIDR,
+										Description:  "Subnet service IPs are allocated from",
+									},
+									"api_server_address": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ForceNew:     true,
+										ValidateFunc: validation.IsIPAddress,
+										Description:  "Address on the Docker host the API server port is bound to, defaults to 127.0.0.1",
+									},
+									"api_server_port": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										ForceNew:     true,
+										ValidateFunc: validation.IsPortNumber,
+										Description:  "Port on the Docker host the API server is bound to, random when not set",
+									},
+									"kube_proxy_mode": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ForceNew:     true,
+										ValidateFunc: validation.StringInSlice([]string{"iptables", "ipvs", "none"}, false),
+										Description:  "kube-proxy mode: iptables, ipvs or none",
+									},
 								},
 							},
 						},
 					},
 				},
 			},
+			"cni": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Install a CNI from a local manifest instead of the default CNI, before waiting for readiness",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"calico", "cilium", "custom"}, false),
+							Description:  "CNI to install: calico, cilium or custom",
+						},
+						"manifest_path": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path to the CNI manifest, e.g. calico.yaml or the output of helm template for cilium",
+						},
+						"images": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Images from the local image cache or paths to image archives loaded into all nodes before the CNI is installed",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -182,12 +726,41 @@ func resourceKindClusterCreate(ctx context.Context, d *schema.ResourceData, m in
 	log.Printf("[INFO] Creating Kind cluster: %s", clusterName)
 
 	// Check if cluster already exists
-	if clusterExists(clusterName) {
+	if clusterExists(config, clusterName) {
 		return diag.Errorf("Kind cluster %s already exists", clusterName)
 	}
 
+	// Resolve the node image
+	nodeImage := d.Get("node_image").(string)
+	if v, ok := d.GetOk("kubernetes_version"); ok {
+		image, err := lookupNodeImage(config, "", v.(string))
+		if err != nil {
+			return diag.Errorf("Failed to resolve node image: %s", err)
+		}
+		nodeImage = image
+	}
+	log.Printf("[INFO] Using node image %s for Kind cluster %s", nodeImage, clusterName)
+
+	// Write the files mounted into the nodes
+	auditLog, err := prepareAudit(d)
+	if err != nil {
+		return diag.Errorf("Failed to prepare audit logging: %s", err)
+	}
+	if err := prepareEncryption(d); err != nil {
+		return diag.Errorf("Failed to prepare encryption at rest: %s", err)
+	}
+	if err := prepareTrustedCAs(d); err != nil {
+		return diag.Errorf("Failed to prepare trusted CA certificates: %s", err)
+	}
+
+	// API servers on remote daemons are reached at the remote host
+	d.Set("api_server_remote_host", remoteAPIServerHost(config))
+
 	// Generate Kind configuration
-	kindConfig := generateKindConfig(d)
+	kindConfig, err := generateKindConfig(d)
+	if err != nil {
+		return diag.Errorf("Failed to generate Kind config: %s", err)
+	}
 	
 	// Create temporary config file
 	configFile, err := os.CreateTemp("", "kind-config-*.yaml")
@@ -208,13 +781,18 @@ func resourceKindClusterCreate(ctx context.Context, d *schema.ResourceData, m in
 	configFile.Close()
 
 	// Create Kind cluster
-	cmd := exec.Command("kind", "create", "cluster", 
+	cmd := kindCommand(config, "create", "cluster",
 		"--name", clusterName,
 		"--config", configFile.Name(),
-		"--image", d.Get("node_image").(string))
-
-	if config.DockerHost != "" {
-		cmd.Env = append(os.Environ(), fmt.Sprintf("DOCKER_HOST=%s", config.DockerHost))
+		"--image", nodeImage)
+	if network := d.Get("network").(string); network != "" {
+		cmd = withEnv(cmd, fmt.Sprintf("KIND_EXPERIMENTAL_DOCKER_NETWORK=%s", network))
+	}
+	noProxy := ""
+	if proxy := clusterProxy(d, config); proxy.enabled() {
+		entries := computeNoProxy(clusterName, kindConfig, proxy.NoProxy)
+		cmd = withEnv(cmd, proxyEnv(proxy, entries)...)
+		noProxy = strings.Join(entries, ",")
 	}
 
 	output, err := cmd.CombinedOutput()
@@ -224,34 +802,123 @@ func resourceKindClusterCreate(ctx context.Context, d *schema.ResourceData, m in
 
 	log.Printf("[INFO] Kind cluster created successfully: %s", clusterName)
 
+	// Set resource ID
+	d.SetId(clusterName)
+	d.Set("audit_log_path", auditLog)
+	d.Set("effective_no_proxy", noProxy)
+	d.Set("resolved_node_image", nodeImage)
+
+	// Set up trusted CAs and registries before images are pulled
+	if err := activateTrustedCAs(d, config, clusterName); err != nil {
+		return diag.Errorf("Failed to activate trusted CA certificates: %s", err)
+	}
+	if err := installRegistries(d, config, clusterName); err != nil {
+		return diag.Errorf("Failed to configure registries: %s", err)
+	}
+
+	// Load images before system components need them
+	if v, ok := d.GetOk("preload_images"); ok {
+		ids, err := preloadImages(config, clusterName, expandStringList(v.([]interface{})), nil)
+		if err != nil {
+			return diag.Errorf("Failed to preload images: %s", err)
+		}
+		d.Set("preloaded_image_ids", ids)
+	}
+
+	// Nodes only become ready once a CNI is installed
+	if diags := installCNI(d, config, clusterName); diags.HasError() {
+		return diags
+	}
+
 	// Wait for cluster to be ready
 	if d.Get("wait_for_ready").(bool) {
-		if err := waitForClusterReady(clusterName); err != nil {
+		if err := waitForClusterReady(config, clusterName); err != nil {
 			return diag.Errorf("Cluster failed to become ready: %s", err)
 		}
 	}
+	if diags := verifyEncryption(d, config, clusterName); diags.HasError() {
+		return diags
+	}
 
-	// Set resource ID
-	d.SetId(clusterName)
+	// Install addons
+	if diags := installIngress(d, config, clusterName); diags.HasError() {
+		return diags
+	}
+	if diags := installLoadBalancer(d, config, clusterName); diags.HasError() {
+		return diags
+	}
+
+	// Apply bootstrap manifests
+	var bootstrapDiags diag.Diagnostics
+	if v, ok := d.GetOk("bootstrap_manifests"); ok {
+		bootstrapDiags = applyBootstrapManifests(config, clusterName, expandStringList(v.([]interface{})))
+		if bootstrapDiags.HasError() {
+			return bootstrapDiags
+		}
+	}
+	if hash, err := hashManifests(expandStringList(d.Get("bootstrap_manifests").([]interface{}))); err == nil {
+		d.Set("bootstrap_manifests_hash", hash)
+	}
+
+	// Stop the cluster if it should be created paused
+	if !d.Get("running").(bool) {
+		if err := stopClusterNodes(config, clusterName); err != nil {
+			return diag.Errorf("Failed to stop Kind cluster: %s", err)
+		}
+	}
 
 	// Get cluster information
-	return resourceKindClusterRead(ctx, d, m)
+	return append(bootstrapDiags, resourceKindClusterRead(ctx, d, m)...)
 }
 
 func resourceKindClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
 	clusterName := d.Id()
 
 	log.Printf("[INFO] Reading Kind cluster: %s", clusterName)
 
 	// Check if cluster exists
-	if !clusterExists(clusterName) {
+	if !clusterExists(config, clusterName) {
 		log.Printf("[WARN] Kind cluster %s not found, removing from state", clusterName)
 		d.SetId("")
 		return nil
 	}
 
+	running, err := clusterRunning(config, clusterName)
+	if err != nil {
+		return diag.Errorf("Failed to get Kind cluster state: %s", err)
+	}
+	d.Set("running", running)
+
+	// Verify the nodes still run the pinned image, a re-pulled tag shows up
+	// as a different digest
+	var diags diag.Diagnostics
+	if digest := imageDigest(expectedNodeImage(d)); digest != "" {
+		nodeDigests, err := getNodeImageDigests(config, clusterName)
+		if err != nil {
+			return diag.Errorf("Failed to verify node image digests: %s", err)
+		}
+		runningDigests, drifted := findNodeImageDrift(nodeDigests, digest)
+		d.Set("node_image_digests", runningDigests)
+		if len(drifted) > 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Node image drift detected",
+				Detail: fmt.Sprintf("Nodes %s of Kind cluster %s do not run the configured image digest %s. Recreate the cluster to restore the pinned image.",
+					strings.Join(drifted, ", "), clusterName, digest),
+			})
+		}
+	}
+
+	// The kubeconfig is read from the control plane, so stopped clusters
+	// keep the values from their last refresh
+	if !running {
+		log.Printf("[INFO] Kind cluster %s is stopped, skipping kubeconfig refresh", clusterName)
+		return diags
+	}
+
 	// Get kubeconfig
-	kubeconfig, err := getKubeconfig(clusterName)
+	kubeconfig, err := getKubeconfig(config, clusterName)
 	if err != nil {
 		return diag.Errorf("Failed to get kubeconfig: %s", err)
 	}
@@ -268,23 +935,78 @@ func resourceKindClusterRead(ctx context.Context, d *schema.ResourceData, m inte
 	// For imported resources, we can't determine the original node_image,
 	// so we'll set it to the default if not already set
 	if d.Get("node_image").(string) == "" {
-		d.Set("node_image", "kindest/node:v1.28.0")
+		d.Set("node_image", defaultNodeImage)
+	}
+	if d.Get("resolved_node_image").(string) == "" {
+		d.Set("resolved_node_image", d.Get("node_image").(string))
 	}
 	
 	// Set computed attributes
 	d.Set("kubeconfig_path", getKubeconfigPath(clusterName))
-	d.Set("endpoint", kubeconfigData.Endpoint)
+	externalHost := d.Get("api_server_external_host").(string)
+	if externalHost == "" {
+		externalHost = d.Get("api_server_remote_host").(string)
+	}
+	d.Set("endpoint", rewriteServerHost(kubeconfigData.Endpoint, externalHost))
+	d.Set("kubeconfig", rewriteKubeconfigServer(kubeconfig, kubeconfigData.Endpoint, externalHost))
 	d.Set("cluster_ca_certificate", kubeconfigData.ClusterCA)
 	d.Set("client_certificate", kubeconfigData.ClientCert)
 	d.Set("client_key", kubeconfigData.ClientKey)
 
-	return nil
+	return diags
 }
 
 func resourceKindClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Kind clusters cannot be updated in place
-	// Any changes should trigger a ForceNew
-	return resourceKindClusterRead(ctx, d, m)
+	config := m.(*ProviderConfig)
+	clusterName := d.Id()
+
+	if d.HasChange("running") {
+		if d.Get("running").(bool) {
+			log.Printf("[INFO] Starting Kind cluster: %s", clusterName)
+			if err := startClusterNodes(config, clusterName); err != nil {
+				return diag.Errorf("Failed to start Kind cluster: %s", err)
+			}
+			if err := waitForClusterReady(config, clusterName); err != nil {
+				return diag.Errorf("Cluster failed to become ready: %s", err)
+			}
+		} else {
+			log.Printf("[INFO] Stopping Kind cluster: %s", clusterName)
+			if err := stopClusterNodes(config, clusterName); err != nil {
+				return diag.Errorf("Failed to stop Kind cluster: %s", err)
+			}
+		}
+	}
+
+	if d.HasChanges("preload_images", "preloaded_image_ids") {
+		if !d.Get("running").(bool) {
+			return diag.Errorf("Cannot load images into stopped Kind cluster %s", clusterName)
+		}
+		loaded, _ := d.GetChange("preloaded_image_ids")
+		ids, err := preloadImages(config, clusterName, expandStringList(d.Get("preload_images").([]interface{})), loaded.(map[string]interface{}))
+		if err != nil {
+			return diag.Errorf("Failed to preload images: %s", err)
+		}
+		d.Set("preloaded_image_ids", ids)
+	}
+
+	var bootstrapDiags diag.Diagnostics
+	if d.HasChanges("bootstrap_manifests", "bootstrap_manifests_hash") {
+		if !d.Get("running").(bool) {
+			return diag.Errorf("Cannot apply bootstrap manifests to stopped Kind cluster %s", clusterName)
+		}
+		entries := expandStringList(d.Get("bootstrap_manifests").([]interface{}))
+		bootstrapDiags = applyBootstrapManifests(config, clusterName, entries)
+		if bootstrapDiags.HasError() {
+			return bootstrapDiags
+		}
+		hash, err := hashManifests(entries)
+		if err != nil {
+			return diag.Errorf("Failed to hash bootstrap manifests: %s", err)
+		}
+		d.Set("bootstrap_manifests_hash", hash)
+	}
+
+	return append(bootstrapDiags, resourceKindClusterRead(ctx, d, m)...)
 }
 
 func resourceKindClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -293,30 +1015,54 @@ func resourceKindClusterDelete(ctx context.Context, d *schema.ResourceData, m in
 
 	log.Printf("[INFO] Deleting Kind cluster: %s", clusterName)
 
-	cmd := exec.Command("kind", "delete", "cluster", "--name", clusterName)
-	
-	if config.DockerHost != "" {
-		cmd.Env = append(os.Environ(), fmt.Sprintf("DOCKER_HOST=%s", config.DockerHost))
-	}
+	cmd := kindCommand(config, "delete", "cluster", "--name", clusterName)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// If cluster doesn't exist, consider it deleted
 		if strings.Contains(string(output), "not found") {
 			log.Printf("[WARN] Kind cluster %s not found, considering it deleted", clusterName)
+			if err := removeClusterDataDir(clusterName); err != nil {
+				return diag.Errorf("Failed to remove generated files of Kind cluster: %s", err)
+			}
 			return nil
 		}
 		return diag.Errorf("Failed to delete Kind cluster: %s\nOutput: %s", err, string(output))
 	}
 
+	if err := removeClusterDataDir(clusterName); err != nil {
+		return diag.Errorf("Failed to remove generated files of Kind cluster: %s", err)
+	}
+
 	log.Printf("[INFO] Kind cluster deleted successfully: %s", clusterName)
 	return nil
 }
 
+// validateNodeImageDigest rejects tag references for node_image when
+// require_node_image_digest is set. Images resolved from kubernetes_version
+// are always pinned.
+func validateNodeImageDigest(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.Get("require_node_image_digest").(bool) {
+		return nil
+	}
+	if _, ok := d.GetOk("kubernetes_version"); ok {
+		return nil
+	}
+	if !d.NewValueKnown("node_image") {
+		return nil
+	}
+
+	image := d.Get("node_image").(string)
+	if imageDigest(image) == "" {
+		return fmt.Errorf("node_image %q must be pinned with an @sha256: digest when require_node_image_digest is set", image)
+	}
+	return nil
+}
+
 // Helper functions
 
-func clusterExists(name string) bool {
-	cmd := exec.Command("kind", "get", "clusters")
+func clusterExists(config *ProviderConfig, name string) bool {
+	cmd := kindCommand(config, "get", "clusters")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -331,7 +1077,19 @@ func clusterExists(name string) bool {
 	return false
 }
 
-func waitForClusterReady(name string) error {
+// apiServerReadyTimeout bounds how long waitForAPIServer waits.
+var (
+	apiServerReadyTimeout  = 5 * time.Minute
+	apiServerReadyInterval = 2 * time.Second
+)
+
+func waitForClusterReady(config *ProviderConfig, name string) error {
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, name)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
 	// Wait up to 5 minutes for cluster to be ready
 	timeout := time.After(5 * time.Minute)
 	ticker := time.NewTicker(5 * time.Second)
@@ -342,12 +1100,12 @@ func waitForClusterReady(name string) error {
 		case <-timeout:
 			return fmt.Errorf("timeout waiting for cluster to be ready")
 		case <-ticker.C:
-			cmd := exec.Command("kubectl", "cluster-info", "--context", fmt.Sprintf("kind-%s", name))
+			cmd := kubectlCommand(kubeconfigPath, "cluster-info")
 			if err := cmd.Run(); err == nil {
 				// Check if all nodes are ready
-				cmd = exec.Command("kubectl", "get", "nodes", "--context", fmt.Sprintf("kind-%s", name))
+				cmd = kubectlCommand(kubeconfigPath, "get", "nodes")
 				output, err := cmd.Output()
-				if err == nil && strings.Contains(string(output), "Ready") {
+				if err == nil && allNodesReady(string(output)) {
 					return nil
 				}
 			}
@@ -355,7 +1113,40 @@ func waitForClusterReady(name string) error {
 	}
 }
 
-func generateKindConfig(d *schema.ResourceData) map[string]interface{} {
+// waitForAPIServer waits until the API server of the cluster described by a
+// kubeconfig file reports itself ready, independent of node readiness.
+func waitForAPIServer(kubeconfigPath string) error {
+	timeout := time.After(apiServerReadyTimeout)
+	for {
+		if err := kubectlCommand(kubeconfigPath, "get", "--raw", "/readyz").Run(); err == nil {
+			return nil
+		}
+		select {
+		case <-timeout:
+			return fmt.Errorf("timeout waiting for the API server to be ready")
+		case <-time.After(apiServerReadyInterval):
+		}
+	}
+}
+
+// allNodesReady parses the output of kubectl get nodes and reports whether
+// every listed node has the Ready status.
+func allNodesReady(output string) bool {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return false
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		// Cordoned nodes report Ready,SchedulingDisabled
+		if len(fields) < 2 || !containsString(strings.Split(fields[1], ","), "Ready") {
+			return false
+		}
+	}
+	return true
+}
+
+func generateKindConfig(d *schema.ResourceData) (map[string]interface{}, error) {
 	// Default configuration
 	config := map[string]interface{}{
 		"kind":       "Cluster",
@@ -440,15 +1231,82 @@ func generateKindConfig(d *schema.ResourceData) map[string]interface{} {
 						}
 						processedNode["extraMounts"] = processedMounts
 					}
+
+					// Process JSON 6902 patches
+					if patches, ok := nodeMap["kubeadm_config_patches_json6902"].([]interface{}); ok && len(patches) > 0 {
+						var processedPatches []map[string]interface{}
+						for _, patch := range patches {
+							patchMap := patch.(map[string]interface{})
+							processedPatches = append(processedPatches, map[string]interface{}{
+								"group":   patchMap["group"],
+								"version": patchMap["version"],
+								"kind":    patchMap["kind"],
+								"patch":   patchMap["patch"],
+							})
+						}
+						processedNode["kubeadmConfigPatchesJSON6902"] = processedPatches
+					}
+
+					// Process kubelet settings
+					if kubelet, ok := nodeMap["kubelet"].([]interface{}); ok && len(kubelet) > 0 && kubelet[0] != nil {
+						for _, patch := range kubeletPatches(kubeletExtraArgs(kubelet[0].(map[string]interface{}))) {
+							appendNodeKubeadmPatch(processedNode, patch)
+						}
+					}
 					
 					processedNodes = append(processedNodes, processedNode)
 				}
 				config["nodes"] = processedNodes
 			}
+
+			// Process networking configuration
+			if networking, ok := customConfig["networking"]; ok {
+				networkingList := networking.([]interface{})
+				if len(networkingList) > 0 && networkingList[0] != nil {
+					networkingMap := networkingList[0].(map[string]interface{})
+					processedNetworking := map[string]interface{}{}
+
+					if disable, ok := networkingMap["disable_default_cni"].(bool); ok && disable {
+						processedNetworking["disableDefaultCNI"] = true
+					}
+					if podSubnet, ok := networkingMap["pod_subnet"].(string); ok && podSubnet != "" {
+						processedNetworking["podSubnet"] = podSubnet
+					}
+					if serviceSubnet, ok := networkingMap["service_subnet"].(string); ok && serviceSubnet != "" {
+						processedNetworking["serviceSubnet"] = serviceSubnet
+					}
+					if address, ok := networkingMap["api_server_address"].(string); ok && address != "" {
+						processedNetworking["apiServerAddress"] = address
+					}
+					if port, ok := networkingMap["api_server_port"].(int); ok && port != 0 {
+						processedNetworking["apiServerPort"] = port
+					}
+					if kubeProxyMode, ok := networkingMap["kube_proxy_mode"].(string); ok && kubeProxyMode != "" {
+						processedNetworking["kubeProxyMode"] = kubeProxyMode
+					}
+
+					if len(processedNetworking) > 0 {
+						config["networking"] = processedNetworking
+					}
+				}
+			}
 		}
 	}
 
-	return config
+	// Generated node settings for addons
+	configureIngress(d, config)
+	configureCNI(d, config)
+	if err := configureTrustedCAs(d, config); err != nil {
+		return nil, fmt.Errorf("failed to configure trusted CA certificates: %s", err)
+	}
+	configureRegistries(d, config)
+
+	// Generated control plane settings
+	if err := configureControlPlane(d, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
 }
 
 type KubeconfigData struct {
@@ -458,8 +1316,8 @@ type KubeconfigData struct {
 	ClientKey  string
 }
 
-func getKubeconfig(clusterName string) (string, error) {
-	cmd := exec.Command("kind", "get", "kubeconfig", "--name", clusterName)
+func getKubeconfig(config *ProviderConfig, clusterName string) (string, error) {
+	cmd := kindCommand(config, "get", "kubeconfig", "--name", clusterName)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -511,4 +1369,4 @@ func parseKubeconfig(kubeconfig string) (*KubeconfigData, error) {
 	}
 
 	return data, nil
-}
\ No newline at end of file
+}
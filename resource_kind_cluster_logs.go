@@ -0,0 +1,290 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKindClusterLogs() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKindClusterLogsCreate,
+		ReadContext:   resourceKindClusterLogsRead,
+		UpdateContext: resourceKindClusterLogsUpdate,
+		DeleteContext: resourceKindClusterLogsDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"cluster_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the Kind cluster to export logs from",
+			},
+			"output_dir": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Directory the logs are exported into, each export goes into its own <cluster_name>-<timestamp> subdirectory",
+			},
+			"archive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Also pack the exported logs into a tar.gz archive next to the export directory",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that cause the logs to be exported again when changed, replacing the previous export",
+			},
+			"export_dir": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subdirectory of output_dir the logs were exported into",
+			},
+			"files": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Exported log files, relative to export_dir",
+			},
+			"archive_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Path to the tar.gz archive when archive is enabled",
+			},
+		},
+	}
+}
+
+func resourceKindClusterLogsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := exportClusterLogs(d, m.(*ProviderConfig)); diags.HasError() {
+		return diags
+	}
+	d.SetId(fmt.Sprintf("%s:%d", d.Get("cluster_name").(string), time.Now().UnixNano()))
+
+	return resourceKindClusterLogsRead(ctx, d, m)
+}
+
+func resourceKindClusterLogsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	exportDir := d.Get("export_dir").(string)
+
+	// Export again if the logs were removed from disk
+	if _, err := os.Stat(exportDir); os.IsNotExist(err) {
+		log.Printf("[WARN] Exported logs in %s not found, removing from state", exportDir)
+		d.SetId("")
+		return nil
+	}
+
+	if archivePath := d.Get("archive_path").(string); archivePath != "" {
+		if _, err := os.Stat(archivePath); os.IsNotExist(err) {
+			log.Printf("[WARN] Log archive %s not found, removing from state", archivePath)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func resourceKindClusterLogsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Only triggers and archive change in place, both export the logs again
+	previousExportDir := d.Get("export_dir").(string)
+	previousArchivePath := d.Get("archive_path").(string)
+
+	if diags := exportClusterLogs(d, m.(*ProviderConfig)); diags.HasError() {
+		return diags
+	}
+	if err := removeLogExport(previousExportDir, previousArchivePath); err != nil {
+		return diag.Errorf("Failed to remove previous log export: %s", err)
+	}
+
+	return resourceKindClusterLogsRead(ctx, d, m)
+}
+
+func resourceKindClusterLogsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Exported logs are build artifacts and are left on disk
+	log.Printf("[INFO] Removing exported logs %s from state, files are kept", d.Id())
+	return nil
+}
+
+// exportClusterLogs exports the logs of the cluster into a new subdirectory of
+// output_dir, so files of other exports or of the user are never touched.
+func exportClusterLogs(d *schema.ResourceData, config *ProviderConfig) diag.Diagnostics {
+	clusterName := d.Get("cluster_name").(string)
+	outputDir := d.Get("output_dir").(string)
+
+	log.Printf("[INFO] Exporting logs of Kind cluster %s to %s", clusterName, outputDir)
+
+	if !clusterExists(config, clusterName) {
+		return diag.Errorf("Kind cluster %s does not exist", clusterName)
+	}
+
+	exportDir, err := createLogExportDir(outputDir, clusterName, time.Now())
+	if err != nil {
+		return diag.Errorf("Failed to create export directory: %s", err)
+	}
+
+	cmd := kindCommand(config, "export", "logs", exportDir, "--name", clusterName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return diag.Errorf("Failed to export Kind cluster logs: %s\nOutput: %s", err, string(output))
+	}
+
+	files, err := listLogFiles(exportDir)
+	if err != nil {
+		return diag.Errorf("Failed to list exported logs: %s", err)
+	}
+
+	archivePath := ""
+	if d.Get("archive").(bool) {
+		archivePath = exportDir + ".tar.gz"
+		if err := createTarGz(exportDir, archivePath); err != nil {
+			return diag.Errorf("Failed to archive exported logs: %s", err)
+		}
+	}
+
+	d.Set("export_dir", exportDir)
+	d.Set("files", files)
+	d.Set("archive_path", archivePath)
+
+	log.Printf("[INFO] Exported %d log files from Kind cluster %s to %s", len(files), clusterName, exportDir)
+	return nil
+}
+
+// createLogExportDir creates the directory of a single export below
+// outputDir, named after the cluster and the time of the export. It fails
+// instead of reusing a directory that already exists.
+func createLogExportDir(outputDir, clusterName string, now time.Time) (string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", err
+	}
+	exportDir := filepath.Join(outputDir, fmt.Sprintf("%s-%s", clusterName, now.UTC().Format("20060102T150405Z")))
+	if err := os.Mkdir(exportDir, 0755); err != nil {
+		return "", err
+	}
+	return exportDir, nil
+}
+
+// removeLogExport removes a previous export and its archive, both recorded
+// in state and created by the provider.
+func removeLogExport(exportDir, archivePath string) error {
+	if exportDir != "" {
+		if err := os.RemoveAll(exportDir); err != nil {
+			return err
+		}
+	}
+	if archivePath != "" {
+		if err := os.Remove(archivePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// listLogFiles returns all regular files below dir as sorted, slash separated
+// relative paths.
+func listLogFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// createTarGz packs the regular files below srcDir into a gzip compressed tar
+// archive at dest. Entries are rooted at the base name of srcDir.
+func createTarGz(srcDir, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	root := filepath.Base(filepath.Clean(srcDir))
+	err = filepath.WalkDir(srcDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(root, rel))
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAccKindClusterLogs_basic tests exporting and archiving logs of a running cluster
+func TestAccKindClusterLogs_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	outputDir := fmt.Sprintf("%s/logs", t.TempDir())
+	resourceName := "kind_cluster_logs.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterLogsConfig_basic(rName, outputDir, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "cluster_name", rName),
+					resource.TestCheckResourceAttrSet(resourceName, "export_dir"),
+					resource.TestCheckResourceAttrSet(resourceName, "files.0"),
+					testAccCheckKindClusterLogsArchiveExists(resourceName),
+				),
+			},
+			// Changing a trigger exports the logs again, replacing the previous export
+			{
+				PreConfig: func() { time.Sleep(time.Second) },
+				Config:    testAccKindClusterLogsConfig_basic(rName, outputDir, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "triggers.run", "2"),
+					testAccCheckKindClusterLogsArchiveExists(resourceName),
+					testAccCheckKindClusterLogsSingleExport(outputDir),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckKindClusterLogsArchiveExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		archivePath := rs.Primary.Attributes["archive_path"]
+		if _, err := os.Stat(archivePath); err != nil {
+			return fmt.Errorf("Log archive %s does not exist: %s", archivePath, err)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckKindClusterLogsSingleExport checks that previous exports were
+// removed, leaving one export directory and its archive.
+func testAccCheckKindClusterLogsSingleExport(outputDir string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		entries, err := os.ReadDir(outputDir)
+		if err != nil {
+			return err
+		}
+		if len(entries) != 2 {
+			return fmt.Errorf("Expected one export and its archive in %s, found %d entries", outputDir, len(entries))
+		}
+		return nil
+	}
+}
+
+func testAccKindClusterLogsConfig_basic(name, outputDir, run string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+}
+
+resource "kind_cluster_logs" "test" {
+  cluster_name = kind_cluster.test.name
+  output_dir   = "%s"
+  archive      = true
+
+  triggers = {
+    run = "%s"
+  }
+}
+`, name, outputDir, run)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListLogFiles tests that exported log files are listed relative to the output directory
+func TestListLogFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "kind-version.txt"), "kind v0.20.0")
+	writeTestFile(t, filepath.Join(dir, "test-control-plane", "kubelet.log"), "kubelet")
+	writeTestFile(t, filepath.Join(dir, "test-control-plane", "containers", "etcd.log"), "etcd")
+
+	files, err := listLogFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"kind-version.txt",
+		"test-control-plane/containers/etcd.log",
+		"test-control-plane/kubelet.log",
+	}, files)
+}
+
+// TestCreateLogExportDir tests that each export gets its own directory next to existing files
+func TestCreateLogExportDir(t *testing.T) {
+	outputDir := t.TempDir()
+	writeTestFile(t, filepath.Join(outputDir, "notes.txt"), "keep me")
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	exportDir, err := createLogExportDir(outputDir, "test", now)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(outputDir, "test-20240501T123000Z"), exportDir)
+	assert.DirExists(t, exportDir)
+	assert.FileExists(t, filepath.Join(outputDir, "notes.txt"))
+
+	// An existing directory is never reused
+	_, err = createLogExportDir(outputDir, "test", now)
+	assert.Error(t, err)
+}
+
+// TestRemoveLogExport tests that only the previous export and its archive are removed
+func TestRemoveLogExport(t *testing.T) {
+	outputDir := t.TempDir()
+	writeTestFile(t, filepath.Join(outputDir, "notes.txt"), "keep me")
+	exportDir := filepath.Join(outputDir, "test-20240501T123000Z")
+	writeTestFile(t, filepath.Join(exportDir, "kind-version.txt"), "kind v0.20.0")
+	writeTestFile(t, exportDir+".tar.gz", "archive")
+
+	require.NoError(t, removeLogExport(exportDir, exportDir+".tar.gz"))
+
+	assert.NoDirExists(t, exportDir)
+	assert.NoFileExists(t, exportDir+".tar.gz")
+	assert.FileExists(t, filepath.Join(outputDir, "notes.txt"))
+
+	// Exports removed by the user are no error
+	assert.NoError(t, removeLogExport(exportDir, exportDir+".tar.gz"))
+}
+
+// TestCreateTarGz tests that the log archive contains all exported files
+func TestCreateTarGz(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	writeTestFile(t, filepath.Join(dir, "kind-version.txt"), "kind v0.20.0")
+	writeTestFile(t, filepath.Join(dir, "test-control-plane", "kubelet.log"), "kubelet")
+
+	archivePath := dir + ".tar.gz"
+	require.NoError(t, createTarGz(dir, archivePath))
+
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	contents := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[header.Name] = string(data)
+	}
+
+	assert.Equal(t, map[string]string{
+		"logs/kind-version.txt":               "kind v0.20.0",
+		"logs/test-control-plane/kubelet.log": "kubelet",
+	}, contents)
+}
+
+// TestClusterLogsResourceSchema tests that the logs resource is re-exported on any input change
+func TestClusterLogsResourceSchema(t *testing.T) {
+	resource := resourceKindClusterLogs()
+
+	assert.True(t, resource.Schema["cluster_name"].Required)
+	assert.True(t, resource.Schema["output_dir"].Required)
+
+	for _, key := range []string{"cluster_name", "output_dir"} {
+		assert.True(t, resource.Schema[key].ForceNew, key)
+	}
+	// Re-exports replace the previous export recorded in state in place
+	for _, key := range []string{"archive", "triggers"} {
+		assert.False(t, resource.Schema[key].ForceNew, key)
+	}
+	assert.NotNil(t, resource.UpdateContext)
+
+	assert.True(t, resource.Schema["export_dir"].Computed)
+	assert.True(t, resource.Schema["files"].Computed)
+	assert.True(t, resource.Schema["archive_path"].Computed)
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,614 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v2"
+)
+
+func resourceKindClusterPeering() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKindClusterPeeringCreate,
+		ReadContext:   resourceKindClusterPeeringRead,
+		DeleteContext: resourceKindClusterPeeringDelete,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_a": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the first Kind cluster",
+			},
+			"cluster_b": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the second Kind cluster, attached to the same Docker network as the first",
+			},
+			"secret_namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "kube-system",
+				ForceNew:    true,
+				Description: "Namespace of the kind-peer-<cluster> Secrets holding the internal kubeconfig of the peer cluster",
+			},
+			"network": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Docker network shared by the nodes of both clusters",
+			},
+			"routes_a": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Routes installed on the nodes of cluster_a, as \"<subnet> via <node address>\"",
+			},
+			"routes_b": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Routes installed on the nodes of cluster_b, as \"<subnet> via <node address>\"",
+			},
+			"kubeconfig_a": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Internal kubeconfig of cluster_a, reachable from cluster_b",
+			},
+			"kubeconfig_b": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Internal kubeconfig of cluster_b, reachable from cluster_a",
+			},
+		},
+	}
+}
+
+// peerNode is a node of a peered cluster.
+type peerNode struct {
+	Name    string
+	Address string
+	PodCIDR string
+}
+
+// peerCluster holds the addressing of a peered cluster.
+type peerCluster struct {
+	Name          string
+	Nodes         []peerNode
+	PodSubnet     string
+	ServiceSubnet string
+}
+
+// peerRoute routes a subnet of the peer cluster through one of its nodes.
+type peerRoute struct {
+	Subnet string
+	Via    string
+}
+
+func (r peerRoute) String() string {
+	return fmt.Sprintf("%s via %s", r.Subnet, r.Via)
+}
+
+func resourceKindClusterPeeringCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	nameA := d.Get("cluster_a").(string)
+	nameB := d.Get("cluster_b").(string)
+
+	log.Printf("[INFO] Peering Kind clusters %s and %s", nameA, nameB)
+
+	if nameA == nameB {
+		return diag.Errorf("A Kind cluster cannot be peered with itself")
+	}
+
+	network, err := sharedClusterNetwork(config, nameA, nameB)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	clusterA, err := getPeerCluster(config, nameA)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	clusterB, err := getPeerCluster(config, nameB)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := validatePeerSubnets(clusterA, clusterB); err != nil {
+		return diag.FromErr(err)
+	}
+
+	routesA := peeringRoutes(clusterB)
+	routesB := peeringRoutes(clusterA)
+	namespace := d.Get("secret_namespace").(string)
+
+	// Undo the routes and Secrets installed so far when a later step fails,
+	// since no ID is set to remove them on destroy
+	rollback := func(diags diag.Diagnostics) diag.Diagnostics {
+		if err := removePeering(config, nameA, nameB, namespace, routesA, routesB); err != nil {
+			log.Printf("[WARN] Failed to roll back peering of Kind clusters %s and %s: %s", nameA, nameB, err)
+		}
+		return diags
+	}
+
+	if err := addPeerRoutes(config, clusterA, routesA); err != nil {
+		return rollback(diag.FromErr(err))
+	}
+	if err := addPeerRoutes(config, clusterB, routesB); err != nil {
+		return rollback(diag.FromErr(err))
+	}
+
+	kubeconfigA, err := getInternalKubeconfig(config, nameA)
+	if err != nil {
+		return rollback(diag.FromErr(err))
+	}
+	kubeconfigB, err := getInternalKubeconfig(config, nameB)
+	if err != nil {
+		return rollback(diag.FromErr(err))
+	}
+
+	// Each cluster gets the kubeconfig of the other one
+	if diags := exportPeerKubeconfig(config, nameB, namespace, nameA, kubeconfigA); diags.HasError() {
+		return rollback(diags)
+	}
+	if diags := exportPeerKubeconfig(config, nameA, namespace, nameB, kubeconfigB); diags.HasError() {
+		return rollback(diags)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", nameA, nameB))
+	d.Set("network", network)
+	d.Set("routes_a", peerRouteStrings(routesA))
+	d.Set("routes_b", peerRouteStrings(routesB))
+	d.Set("kubeconfig_a", kubeconfigA)
+	d.Set("kubeconfig_b", kubeconfigB)
+
+	log.Printf("[INFO] Kind clusters %s and %s peered successfully", nameA, nameB)
+
+	return resourceKindClusterPeeringRead(ctx, d, m)
+}
+
+func resourceKindClusterPeeringRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+
+	log.Printf("[INFO] Reading Kind cluster peering: %s", d.Id())
+
+	for _, side := range []string{"a", "b"} {
+		clusterName := d.Get("cluster_" + side).(string)
+		if !clusterExists(config, clusterName) {
+			log.Printf("[WARN] Kind cluster %s not found, removing peering from state", clusterName)
+			d.SetId("")
+			return nil
+		}
+
+		// Routes do not survive node restarts, peer again when any is gone
+		present, err := peerRoutesPresent(config, clusterName, d.Get("routes_"+side).([]interface{}))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !present {
+			log.Printf("[WARN] Peering routes missing on Kind cluster %s, removing peering from state", clusterName)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func resourceKindClusterPeeringDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	nameA := d.Get("cluster_a").(string)
+	nameB := d.Get("cluster_b").(string)
+	namespace := d.Get("secret_namespace").(string)
+
+	log.Printf("[INFO] Removing peering of Kind clusters %s and %s", nameA, nameB)
+
+	routesA := parsePeerRoutes(d.Get("routes_a").([]interface{}))
+	routesB := parsePeerRoutes(d.Get("routes_b").([]interface{}))
+	if err := removePeering(config, nameA, nameB, namespace, routesA, routesB); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// removePeering deletes the routes and kubeconfig Secrets of a peering from
+// both clusters. Clusters deleted along with the peering need no cleanup.
+func removePeering(config *ProviderConfig, nameA, nameB, namespace string, routesA, routesB []peerRoute) error {
+	for _, side := range []struct {
+		cluster, peer string
+		routes        []peerRoute
+	}{
+		{nameA, nameB, routesA},
+		{nameB, nameA, routesB},
+	} {
+		if !clusterExists(config, side.cluster) {
+			continue
+		}
+		if err := deletePeerRoutes(config, side.cluster, side.routes); err != nil {
+			return err
+		}
+		if err := deletePeerKubeconfig(config, side.cluster, namespace, side.peer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sharedClusterNetwork returns the Docker network the nodes of both clusters
+// are attached to.
+func sharedClusterNetwork(config *ProviderConfig, nameA, nameB string) (string, error) {
+	networksA, err := getClusterNetworks(config, nameA)
+	if err != nil {
+		return "", err
+	}
+	networksB, err := getClusterNetworks(config, nameB)
+	if err != nil {
+		return "", err
+	}
+
+	for _, network := range networksA {
+		for _, other := range networksB {
+			if network == other {
+				return network, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("Kind clusters %s and %s do not share a Docker network (%s vs %s), set the same network on both clusters",
+		nameA, nameB, strings.Join(networksA, ", "), strings.Join(networksB, ", "))
+}
+
+// getClusterNetworks returns the Docker networks the first node of a
+// cluster is attached to.
+func getClusterNetworks(config *ProviderConfig, clusterName string) ([]string, error) {
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %s has no nodes", clusterName)
+	}
+
+	output, err := dockerCommand(config, "inspect", "--format",
+		"{{range $name, $network := .NetworkSettings.Networks}}{{println $name}}{{end}}", nodes[0]).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect node %s: %s", nodes[0], err)
+	}
+	networks := strings.Fields(string(output))
+	sort.Strings(networks)
+	return networks, nil
+}
+
+// peerNodesJSONPath prints one line per node with its name, internal address
+// and pod CIDR.
+const peerNodesJSONPath = `{range .items[*]}{.metadata.name}{" "}{.status.addresses[?(@.type=="InternalIP")].address}{" "}{.spec.podCIDR}{"\n"}{end}`
+
+// getPeerCluster reads the node addresses and subnets of a cluster.
+func getPeerCluster(config *ProviderConfig, clusterName string) (*peerCluster, error) {
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	output, err := kubectlCommand(kubeconfigPath, "get", "nodes", "-o", "jsonpath="+peerNodesJSONPath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes of cluster %s: %s", clusterName, err)
+	}
+	nodes, err := parsePeerNodes(string(output))
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %s", clusterName, err)
+	}
+
+	output, err = kubectlCommand(kubeconfigPath, "get", "configmap", "kubeadm-config", "-n", "kube-system",
+		"-o", "jsonpath={.data.ClusterConfiguration}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeadm configuration of cluster %s: %s", clusterName, err)
+	}
+	podSubnet, serviceSubnet, err := parseClusterSubnets(string(output))
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %s", clusterName, err)
+	}
+
+	return &peerCluster{
+		Name:          clusterName,
+		Nodes:         nodes,
+		PodSubnet:     podSubnet,
+		ServiceSubnet: serviceSubnet,
+	}, nil
+}
+
+// parsePeerNodes parses kubectl output formatted with peerNodesJSONPath.
+func parsePeerNodes(output string) ([]peerNode, error) {
+	var nodes []peerNode
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("node %s has no internal address", fields[0])
+		}
+		node := peerNode{Name: fields[0], Address: fields[1]}
+		if len(fields) > 2 {
+			node.PodCIDR = fields[2]
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes found")
+	}
+	return nodes, nil
+}
+
+// parseClusterSubnets extracts the pod and service subnets from a kubeadm
+// ClusterConfiguration.
+func parseClusterSubnets(clusterConfiguration string) (string, string, error) {
+	var parsed struct {
+		Networking struct {
+			PodSubnet     string `yaml:"podSubnet"`
+			ServiceSubnet string `yaml:"serviceSubnet"`
+		} `yaml:"networking"`
+	}
+	if err := yaml.Unmarshal([]byte(clusterConfiguration), &parsed); err != nil {
+		return "", "", fmt.Errorf("failed to parse kubeadm configuration: %s", err)
+	}
+	if parsed.Networking.PodSubnet == "" || parsed.Networking.ServiceSubnet == "" {
+		return "", "", fmt.Errorf("kubeadm configuration has no pod or service subnet")
+	}
+	return parsed.Networking.PodSubnet, parsed.Networking.ServiceSubnet, nil
+}
+
+// validatePeerSubnets ensures traffic to the peer's subnets is not captured
+// by the cluster's own subnets. Kind uses the same defaults for every
+// cluster, so at least one side needs custom networking settings.
+func validatePeerSubnets(a, b *peerCluster) error {
+	for _, subnetA := range []string{a.PodSubnet, a.ServiceSubnet} {
+		for _, subnetB := range []string{b.PodSubnet, b.ServiceSubnet} {
+			overlap, err := subnetsOverlap(subnetA, subnetB)
+			if err != nil {
+				return err
+			}
+			if overlap {
+				return fmt.Errorf("subnet %s of Kind cluster %s overlaps subnet %s of Kind cluster %s, set distinct pod_subnet and service_subnet in kind_config networking",
+					subnetA, a.Name, subnetB, b.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// subnetsOverlap reports whether two comma separated lists of CIDRs, as used
+// for dual-stack subnets, share any address.
+func subnetsOverlap(a, b string) (bool, error) {
+	for _, cidrA := range strings.Split(a, ",") {
+		_, netA, err := net.ParseCIDR(strings.TrimSpace(cidrA))
+		if err != nil {
+			return false, fmt.Errorf("invalid subnet %q: %s", cidrA, err)
+		}
+		for _, cidrB := range strings.Split(b, ",") {
+			_, netB, err := net.ParseCIDR(strings.TrimSpace(cidrB))
+			if err != nil {
+				return false, fmt.Errorf("invalid subnet %q: %s", cidrB, err)
+			}
+			if netA.Contains(netB.IP) || netB.Contains(netA.IP) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// peeringRoutes returns the routes to the subnets of a peer cluster. Pod
+// CIDRs are routed to the node owning them, the service subnet to the first
+// control plane node whose kube-proxy forwards it.
+func peeringRoutes(peer *peerCluster) []peerRoute {
+	var routes []peerRoute
+	serviceNode := peer.Nodes[0]
+	for _, node := range peer.Nodes {
+		if node.PodCIDR != "" {
+			routes = append(routes, peerRoute{Subnet: node.PodCIDR, Via: node.Address})
+		}
+		if strings.HasSuffix(node.Name, "-control-plane") {
+			serviceNode = node
+		}
+	}
+	for _, subnet := range strings.Split(peer.ServiceSubnet, ",") {
+		routes = append(routes, peerRoute{Subnet: strings.TrimSpace(subnet), Via: serviceNode.Address})
+	}
+	return routes
+}
+
+func peerRouteStrings(routes []peerRoute) []string {
+	result := make([]string, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, route.String())
+	}
+	return result
+}
+
+// addPeerRoutes installs routes on every node of a cluster.
+func addPeerRoutes(config *ProviderConfig, cluster *peerCluster, routes []peerRoute) error {
+	for _, node := range cluster.Nodes {
+		for _, route := range routes {
+			args := append([]string{"exec", node.Name, "ip"}, routeArgs(route, "replace")...)
+			output, err := dockerCommand(config, args...).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("failed to add route %s on node %s: %s\nOutput: %s", route, node.Name, err, string(output))
+			}
+		}
+	}
+	return nil
+}
+
+// routeArgs builds ip route arguments, using the IPv6 family for IPv6 subnets.
+func routeArgs(route peerRoute, action string) []string {
+	var args []string
+	if strings.Contains(route.Subnet, ":") {
+		args = append(args, "-6")
+	}
+	return append(args, "route", action, route.Subnet, "via", route.Via)
+}
+
+// parsePeerRoutes parses routes stored in state.
+func parsePeerRoutes(routes []interface{}) []peerRoute {
+	var result []peerRoute
+	for _, route := range routes {
+		fields := strings.Fields(route.(string))
+		if len(fields) == 3 && fields[1] == "via" {
+			result = append(result, peerRoute{Subnet: fields[0], Via: fields[2]})
+		}
+	}
+	return result
+}
+
+// peerRoutesPresent reports whether all routes are installed on every node
+// of a cluster. Stopped clusters are checked again once they are started.
+func peerRoutesPresent(config *ProviderConfig, clusterName string, routes []interface{}) (bool, error) {
+	running, err := clusterRunning(config, clusterName)
+	if err != nil {
+		return false, err
+	}
+	if !running {
+		return true, nil
+	}
+
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return false, err
+	}
+	for _, node := range nodes {
+		for _, route := range parsePeerRoutes(routes) {
+			args := []string{"exec", node, "ip"}
+			if strings.Contains(route.Subnet, ":") {
+				args = append(args, "-6")
+			}
+			args = append(args, "route", "show", route.Subnet)
+			output, err := dockerCommand(config, args...).Output()
+			if err != nil {
+				return false, fmt.Errorf("failed to list routes on node %s: %s", node, err)
+			}
+			if !strings.Contains(string(output), "via "+route.Via+" ") {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// deletePeerRoutes removes routes from the running nodes of a cluster,
+// ignoring routes that are already gone.
+func deletePeerRoutes(config *ProviderConfig, clusterName string, routes []peerRoute) error {
+	running, err := clusterRunning(config, clusterName)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return nil
+	}
+
+	nodes, err := getClusterNodes(config, clusterName)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		for _, route := range routes {
+			args := append([]string{"exec", node, "ip"}, routeArgs(route, "del")...)
+			if output, err := dockerCommand(config, args...).CombinedOutput(); err != nil {
+				log.Printf("[DEBUG] Removing route %s on node %s failed: %s\nOutput: %s", route, node, err, string(output))
+			}
+		}
+	}
+	return nil
+}
+
+// getInternalKubeconfig returns the kubeconfig of a cluster that addresses
+// the API server on the Docker network instead of the host port.
+func getInternalKubeconfig(config *ProviderConfig, clusterName string) (string, error) {
+	output, err := kindCommand(config, "get", "kubeconfig", "--internal", "--name", clusterName).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get internal kubeconfig of cluster %s: %s", clusterName, err)
+	}
+	return string(output), nil
+}
+
+// peerKubeconfigSecret renders the Secret holding the kubeconfig of a peer
+// cluster.
+func peerKubeconfigSecret(namespace, peerName, kubeconfig string) (string, error) {
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      "kind-peer-" + peerName,
+			"namespace": namespace,
+			"labels": map[string]string{
+				"app.kubernetes.io/managed-by": manifestFieldManager,
+			},
+		},
+		"type": "Opaque",
+		"stringData": map[string]string{
+			"kubeconfig": kubeconfig,
+		},
+	}
+	data, err := yaml.Marshal(secret)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// exportPeerKubeconfig stores the internal kubeconfig of a peer cluster as a
+// Secret in a cluster.
+func exportPeerKubeconfig(config *ProviderConfig, clusterName, namespace, peerName, kubeconfig string) diag.Diagnostics {
+	manifest, err := peerKubeconfigSecret(namespace, peerName, kubeconfig)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	documents, err := parseManifest(manifest)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	return applyManifestDocuments(kubeconfigPath, documents)
+}
+
+// deletePeerKubeconfig removes the Secret of a peer cluster, skipping
+// stopped clusters.
+func deletePeerKubeconfig(config *ProviderConfig, clusterName, namespace, peerName string) error {
+	running, err := clusterRunning(config, clusterName)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return nil
+	}
+
+	manifest, err := peerKubeconfigSecret(namespace, peerName, "")
+	if err != nil {
+		return err
+	}
+	documents, err := parseManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return deleteManifestDocument(kubeconfigPath, documents[0])
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccKindClusterPeering_basic tests routing between two clusters with distinct subnets
+func TestAccKindClusterPeering_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster_peering.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterPeeringConfig_basic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "network", "kind"),
+					resource.TestCheckResourceAttr(resourceName, "routes_a.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "routes_b.#", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "kubeconfig_a"),
+					resource.TestCheckResourceAttrSet(resourceName, "kubeconfig_b"),
+					testAccCheckKindClusterObjectExists("kind_cluster.east", "secret", "kind-peer-"+rName+"-west"),
+					testAccCheckKindClusterObjectExists("kind_cluster.west", "secret", "kind-peer-"+rName+"-east"),
+				),
+			},
+		},
+	})
+}
+
+func testAccKindClusterPeeringConfig_basic(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "east" {
+  name = "%[1]s-east"
+  kind_config {
+    networking {
+      pod_subnet     = "10.10.0.0/16"
+      service_subnet = "10.110.0.0/16"
+    }
+  }
+}
+
+resource "kind_cluster" "west" {
+  name = "%[1]s-west"
+  kind_config {
+    networking {
+      pod_subnet     = "10.20.0.0/16"
+      service_subnet = "10.120.0.0/16"
+    }
+  }
+}
+
+resource "kind_cluster_peering" "test" {
+  cluster_a        = kind_cluster.east.name
+  cluster_b        = kind_cluster.west.name
+  secret_namespace = "default"
+}
+`, name)
+}
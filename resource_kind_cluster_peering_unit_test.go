@@ -0,0 +1,123 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParsePeerNodes tests parsing node addresses and pod CIDRs
+func TestParsePeerNodes(t *testing.T) {
+	nodes, err := parsePeerNodes("east-control-plane 172.18.0.2 10.10.0.0/24\neast-worker 172.18.0.3 10.10.1.0/24\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, []peerNode{
+		{Name: "east-control-plane", Address: "172.18.0.2", PodCIDR: "10.10.0.0/24"},
+		{Name: "east-worker", Address: "172.18.0.3", PodCIDR: "10.10.1.0/24"},
+	}, nodes)
+}
+
+// TestParsePeerNodes_errors tests rejecting nodes without addresses and empty clusters
+func TestParsePeerNodes_errors(t *testing.T) {
+	_, err := parsePeerNodes("east-control-plane\n")
+	assert.Error(t, err)
+
+	_, err = parsePeerNodes("")
+	assert.Error(t, err)
+}
+
+// TestParseClusterSubnets tests reading the subnets from the kubeadm ClusterConfiguration
+func TestParseClusterSubnets(t *testing.T) {
+	podSubnet, serviceSubnet, err := parseClusterSubnets(`apiVersion: kubeadm.k8s.io/v1beta3
+kind: ClusterConfiguration
+networking:
+  dnsDomain: cluster.local
+  podSubnet: 10.10.0.0/16
+  serviceSubnet: 10.110.0.0/16
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "10.10.0.0/16", podSubnet)
+	assert.Equal(t, "10.110.0.0/16", serviceSubnet)
+
+	_, _, err = parseClusterSubnets("kind: ClusterConfiguration\n")
+	assert.Error(t, err)
+}
+
+// TestSubnetsOverlap tests overlap detection including dual-stack subnets
+func TestSubnetsOverlap(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "10.244.0.0/16", b: "10.244.0.0/16", expected: true},
+		{a: "10.96.0.0/12", b: "10.100.0.0/16", expected: true},
+		{a: "10.244.0.0/16", b: "10.245.0.0/16", expected: false},
+		{a: "10.244.0.0/16,fd00:10:244::/56", b: "10.245.0.0/16,fd00:10:244::/56", expected: true},
+	}
+
+	for _, tt := range tests {
+		overlap, err := subnetsOverlap(tt.a, tt.b)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, overlap, "%s %s", tt.a, tt.b)
+	}
+
+	_, err := subnetsOverlap("10.244.0.0", "10.245.0.0/16")
+	assert.Error(t, err)
+}
+
+// TestValidatePeerSubnets tests that clusters with kind's default subnets are rejected
+func TestValidatePeerSubnets(t *testing.T) {
+	east := &peerCluster{Name: "east", PodSubnet: "10.244.0.0/16", ServiceSubnet: "10.96.0.0/16"}
+	west := &peerCluster{Name: "west", PodSubnet: "10.244.0.0/16", ServiceSubnet: "10.96.0.0/16"}
+	assert.Error(t, validatePeerSubnets(east, west))
+
+	west = &peerCluster{Name: "west", PodSubnet: "10.245.0.0/16", ServiceSubnet: "10.97.0.0/16"}
+	assert.NoError(t, validatePeerSubnets(east, west))
+}
+
+// TestPeeringRoutes tests routing pod CIDRs to their nodes and services to the control plane
+func TestPeeringRoutes(t *testing.T) {
+	peer := &peerCluster{
+		Name: "west",
+		Nodes: []peerNode{
+			{Name: "west-worker", Address: "172.18.0.5", PodCIDR: "10.245.1.0/24"},
+			{Name: "west-control-plane", Address: "172.18.0.4", PodCIDR: "10.245.0.0/24"},
+		},
+		ServiceSubnet: "10.97.0.0/16",
+	}
+
+	routes := peeringRoutes(peer)
+
+	assert.Equal(t, []string{
+		"10.245.1.0/24 via 172.18.0.5",
+		"10.245.0.0/24 via 172.18.0.4",
+		"10.97.0.0/16 via 172.18.0.4",
+	}, peerRouteStrings(routes))
+}
+
+// TestParsePeerRoutes tests reading routes back from state
+func TestParsePeerRoutes(t *testing.T) {
+	routes := parsePeerRoutes([]interface{}{"10.245.0.0/24 via 172.18.0.4", "invalid"})
+	assert.Equal(t, []peerRoute{{Subnet: "10.245.0.0/24", Via: "172.18.0.4"}}, routes)
+}
+
+// TestRouteArgs tests selecting the address family of a route
+func TestRouteArgs(t *testing.T) {
+	assert.Equal(t, []string{"route", "replace", "10.245.0.0/24", "via", "172.18.0.4"},
+		routeArgs(peerRoute{Subnet: "10.245.0.0/24", Via: "172.18.0.4"}, "replace"))
+	assert.Equal(t, []string{"-6", "route", "del", "fd00:10:245::/64", "via", "fc00:f853:ccd:e793::4"},
+		routeArgs(peerRoute{Subnet: "fd00:10:245::/64", Via: "fc00:f853:ccd:e793::4"}, "del"))
+}
+
+// TestPeerKubeconfigSecret tests the Secret exporting a peer kubeconfig
+func TestPeerKubeconfigSecret(t *testing.T) {
+	manifest, err := peerKubeconfigSecret("kube-system", "west", "apiVersion: v1\nkind: Config\n")
+	require.NoError(t, err)
+
+	documents, err := parseManifest(manifest)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	assert.Equal(t, "Secret kube-system/kind-peer-west", documents[0].Object.String())
+	assert.Contains(t, manifest, "kind: Config")
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -27,7 +29,7 @@ func TestAccKindCluster_basic(t *testing.T) {
 					testAccCheckKindClusterExists(resourceName),
 					resource.TestCheckResourceAttr(resourceName, "name", rName),
 					resource.TestCheckResourceAttr(resourceName, "wait_for_ready", "true"),
-					resource.TestCheckResourceAttr(resourceName, "node_image", "kindest/node:v1.28.0"),
+					resource.TestCheckResourceAttr(resourceName, "node_image", defaultNodeImage),
 					resource.TestCheckResourceAttrSet(resourceName, "endpoint"),
 					resource.TestCheckResourceAttrSet(resourceName, "kubeconfig_path"),
 					resource.TestCheckResourceAttrSet(resourceName, "cluster_ca_certificate"),
@@ -97,6 +99,28 @@ func TestAccKindCluster_customNodeImage(t *testing.T) {
 	})
 }
 
+// TestAccKindCluster_kubernetesVersion tests resolving a Kubernetes version to a pinned node image
+func TestAccKindCluster_kubernetesVersion(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_kubernetesVersion(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "kubernetes_version", "1.27"),
+					resource.TestMatchResourceAttr(resourceName, "resolved_node_image", regexp.MustCompile(`^kindest/node:v1\.27\.\d+@sha256:`)),
+				),
+			},
+		},
+	})
+}
+
 // TestAccKindCluster_multipleNodes tests creation with multiple worker nodes
 func TestAccKindCluster_multipleNodes(t *testing.T) {
 	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
@@ -119,6 +143,326 @@ func TestAccKindCluster_multipleNodes(t *testing.T) {
 	})
 }
 
+// TestAccKindCluster_stopStart tests stopping and starting a cluster in place
+func TestAccKindCluster_stopStart(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_running(rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "running", "false"),
+				),
+			},
+			{
+				Config: testAccKindClusterConfig_running(rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "running", "true"),
+					testAccCheckKindClusterNodeCount(resourceName, 1),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_bootstrapManifests tests applying manifests after cluster creation
+func TestAccKindCluster_bootstrapManifests(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_bootstrapManifests(rName, "bootstrap"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "bootstrap_manifests_hash"),
+					testAccCheckKindClusterObjectExists(resourceName, "namespace", "bootstrap"),
+				),
+			},
+			// Changed manifests are applied in place
+			{
+				Config: testAccKindClusterConfig_bootstrapManifests(rName, "bootstrap-updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterExists(resourceName),
+					testAccCheckKindClusterObjectExists(resourceName, "namespace", "bootstrap-updated"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_ingress tests installing the ingress-nginx addon
+func TestAccKindCluster_ingress(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_ingress(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "ingress.0.controller", "nginx"),
+					testAccCheckKindClusterObjectExists(resourceName, "ingressclass", "nginx"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_loadBalancer tests installing MetalLB with a pool from the kind network
+func TestAccKindCluster_loadBalancer(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_loadBalancer(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resourceName, "load_balancer.0.address_range", regexp.MustCompile(`^\d+\.\d+\.\d+\.200-\d+\.\d+\.\d+\.250$`)),
+					testAccCheckKindClusterObjectExists(resourceName, "crd", "ipaddresspools.metallb.io"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_oidc tests configuring OIDC authentication on the API server
+func TestAccKindCluster_oidc(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_oidc(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "oidc.0.client_id", "kubernetes"),
+					testAccCheckKindClusterAPIServerFlag(resourceName, "--oidc-issuer-url=https://dex.example.com"),
+					testAccCheckKindClusterAPIServerFlag(resourceName, "--oidc-username-claim=email"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_audit tests audit logging to the host
+func TestAccKindCluster_audit(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_audit(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterAPIServerFlag(resourceName, "--audit-policy-file=/etc/kubernetes/audit/policy.yaml"),
+					resource.TestCheckResourceAttrWith(resourceName, "audit_log_path", func(path string) error {
+						_, err := os.Stat(path)
+						return err
+					}),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_encryptionAtRest tests that secrets are stored encrypted in etcd
+func TestAccKindCluster_encryptionAtRest(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Create fails when the verification secret is not encrypted
+				Config: testAccKindClusterConfig_encryptionAtRest(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterAPIServerFlag(resourceName, "--encryption-provider-config=/etc/kubernetes/encryption/config.yaml"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_extraArgs tests structured component flags and kubelet settings
+func TestAccKindCluster_extraArgs(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_extraArgs(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterAPIServerFlag(resourceName, "--v=4"),
+					resource.TestCheckResourceAttr(resourceName, "kind_config.0.node.1.kubelet.0.max_pods", "250"),
+					// Kubelet settings apply to their own node only
+					testAccCheckKindClusterNodePodCapacity(resourceName, "worker", "250"),
+					testAccCheckKindClusterNodePodCapacity(resourceName, "control-plane", "110"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_trustedCACertificates tests adding CA certificates to the node trust store
+func TestAccKindCluster_trustedCACertificates(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_trustedCACertificates(rName, testCACertificate(t)),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						node := fmt.Sprintf("%s-worker", rName)
+						cmd := exec.Command("docker", "exec", node, "test", "-e", "/etc/ssl/certs/ca-0.pem")
+						if output, err := cmd.CombinedOutput(); err != nil {
+							return fmt.Errorf("CA certificate not trusted on %s: %s\nOutput: %s", node, err, string(output))
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_proxy tests passing proxy settings with a computed NO_PROXY to the nodes
+func TestAccKindCluster_proxy(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_proxy(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resourceName, "effective_no_proxy",
+						regexp.MustCompile(`^registry\.internal,localhost,.*,\.svc,\.cluster\.local,`+rName+`-control-plane$`)),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_registry tests writing registry mirrors into the containerd configuration
+func TestAccKindCluster_registry(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_registry(rName),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						node := fmt.Sprintf("%s-control-plane", rName)
+						cmd := exec.Command("docker", "exec", node, "grep", "mirror.gcr.io", "/etc/containerd/certs.d/docker.io/hosts.toml")
+						if output, err := cmd.CombinedOutput(); err != nil {
+							return fmt.Errorf("registry mirror not configured on %s: %s\nOutput: %s", node, err, string(output))
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_externalHost tests rewriting the endpoint to an external host
+func TestAccKindCluster_externalHost(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_externalHost(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resourceName, "endpoint", regexp.MustCompile(`^https://localhost:\d+$`)),
+					resource.TestMatchResourceAttr(resourceName, "kubeconfig", regexp.MustCompile(`server: https://localhost:\d+`)),
+				),
+			},
+		},
+	})
+}
+
+// TestAccKindCluster_dockerTLS tests creating a cluster on a TLS-protected
+// daemon, e.g. a local dockerd started with certificates generated for the
+// test. It runs when KIND_TEST_DOCKER_TLS_HOST and KIND_TEST_DOCKER_CERT_PATH
+// are set.
+func TestAccKindCluster_dockerTLS(t *testing.T) {
+	dockerHost := os.Getenv("KIND_TEST_DOCKER_TLS_HOST")
+	certPath := os.Getenv("KIND_TEST_DOCKER_CERT_PATH")
+	if dockerHost == "" || certPath == "" {
+		t.Skip("KIND_TEST_DOCKER_TLS_HOST and KIND_TEST_DOCKER_CERT_PATH must be set for Docker TLS tests")
+	}
+
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_cluster.test"
+
+	// The checks below call the CLIs directly against the TLS daemon
+	t.Setenv("DOCKER_HOST", dockerHost)
+	t.Setenv("DOCKER_CERT_PATH", certPath)
+	t.Setenv("DOCKER_TLS_VERIFY", "1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindClusterConfig_dockerTLS(rName, dockerHost, certPath),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckKindClusterExists(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "endpoint"),
+				),
+			},
+		},
+	})
+}
+
 // TestAccKindCluster_disappears tests that the resource handles external deletion
 func TestAccKindCluster_disappears(t *testing.T) {
 	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
@@ -166,7 +510,7 @@ func testAccCheckKindClusterExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No Kind cluster ID is set")
 		}
 
-		if !clusterExists(rs.Primary.ID) {
+		if !clusterExists(nil, rs.Primary.ID) {
 			return fmt.Errorf("Kind cluster %s does not exist", rs.Primary.ID)
 		}
 
@@ -180,7 +524,7 @@ func testAccCheckKindClusterDestroy(s *terraform.State) error {
 			continue
 		}
 
-		if clusterExists(rs.Primary.ID) {
+		if clusterExists(nil, rs.Primary.ID) {
 			return fmt.Errorf("Kind cluster %s still exists", rs.Primary.ID)
 		}
 	}
@@ -264,6 +608,63 @@ func testAccCheckKindClusterNodeCount(n string, expectedCount int) resource.Test
 	}
 }
 
+func testAccCheckKindClusterObjectExists(n, kind, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		cmd := exec.Command("kubectl", "get", kind, name, "--context", fmt.Sprintf("kind-%s", rs.Primary.ID))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("Failed to get %s %s: %s\nOutput: %s", kind, name, err, string(output))
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckKindClusterAPIServerFlag checks the static pod manifest of the
+// API server on the first control-plane node for a flag.
+func testAccCheckKindClusterAPIServerFlag(n, flag string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		node := fmt.Sprintf("%s-control-plane", rs.Primary.ID)
+		cmd := exec.Command("docker", "exec", node, "grep", "--", flag, "/etc/kubernetes/manifests/kube-apiserver.yaml")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("API server flag %s not found on %s: %s\nOutput: %s", flag, node, err, string(output))
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckKindClusterNodePodCapacity(n, role, pods string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		node := fmt.Sprintf("%s-%s", rs.Primary.ID, role)
+		cmd := exec.Command("kubectl", "get", "node", node, "--context", fmt.Sprintf("kind-%s", rs.Primary.ID),
+			"-o", "jsonpath={.status.capacity.pods}")
+		output, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("Failed to get pod capacity of %s: %s", node, err)
+		}
+		if capacity := strings.TrimSpace(string(output)); capacity != pods {
+			return fmt.Errorf("Node %s has pod capacity %s, expected %s", node, capacity, pods)
+		}
+
+		return nil
+	}
+}
+
 // Test configurations
 
 func testAccKindClusterConfig_basic(name string) string {
@@ -318,6 +719,15 @@ resource "kind_cluster" "test" {
 `, name)
 }
 
+func testAccKindClusterConfig_kubernetesVersion(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name               = "%s"
+  kubernetes_version = "1.27"
+}
+`, name)
+}
+
 func testAccKindClusterConfig_multipleWorkers(name string) string {
 	return fmt.Sprintf(`
 resource "kind_cluster" "test" {
@@ -342,4 +752,219 @@ resource "kind_cluster" "test" {
   }
 }
 `, name)
-}
\ No newline at end of file
+}
+
+func testAccKindClusterConfig_running(name string, running bool) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name    = "%s"
+  running = %t
+}
+`, name, running)
+}
+
+func testAccKindClusterConfig_bootstrapManifests(name, namespace string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  bootstrap_manifests = [
+    <<-EOT
+    apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: %s
+    EOT
+  ]
+}
+`, name, namespace)
+}
+
+func testAccKindClusterConfig_ingress(name string) string {
+	basePort := 9000 + (int(acctest.RandInt()) % 1000)
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  ingress {
+    http_port  = %d
+    https_port = %d
+  }
+}
+`, name, basePort, basePort+1)
+}
+
+func testAccKindClusterConfig_loadBalancer(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  load_balancer {}
+}
+`, name)
+}
+
+func testAccKindClusterConfig_oidc(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  oidc {
+    issuer_url     = "https://dex.example.com"
+    client_id      = "kubernetes"
+    username_claim = "email"
+  }
+}
+`, name)
+}
+
+func testAccKindClusterConfig_audit(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name           = "%s"
+  wait_for_ready = true
+
+  audit {
+    policy = <<-EOT
+      apiVersion: audit.k8s.io/v1
+      kind: Policy
+      rules:
+      - level: Metadata
+      EOT
+  }
+}
+`, name)
+}
+
+func testAccKindClusterConfig_encryptionAtRest(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name           = "%s"
+  wait_for_ready = true
+
+  encryption_at_rest {
+    provider {
+      type = "aescbc"
+      key {
+        name   = "key1"
+        secret = "c2VjcmV0LWtleS1mb3ItdGVzdHMtb25seS0zMmJ5dGU="
+      }
+    }
+    provider {
+      type = "identity"
+    }
+  }
+}
+`, name)
+}
+
+func testAccKindClusterConfig_extraArgs(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name           = "%s"
+  wait_for_ready = true
+
+  api_server_extra_args = {
+    v = "4"
+  }
+
+  kind_config {
+    node {
+      role = "control-plane"
+    }
+    node {
+      role = "worker"
+      kubelet {
+        max_pods = 250
+        system_reserved = {
+          cpu    = "100m"
+          memory = "100Mi"
+        }
+      }
+    }
+  }
+}
+`, name)
+}
+
+func testAccKindClusterConfig_trustedCACertificates(name, certificate string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  trusted_ca_certificates = [
+    <<-EOT
+%sEOT
+  ]
+
+  kind_config {
+    node {
+      role = "control-plane"
+    }
+    node {
+      role = "worker"
+    }
+  }
+}
+`, name, certificate)
+}
+
+func testAccKindClusterConfig_proxy(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  proxy {
+    https_proxy = "http://proxy.invalid:3128"
+    no_proxy    = ["registry.internal"]
+  }
+}
+`, name)
+}
+
+func testAccKindClusterConfig_registry(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  registry {
+    host      = "docker.io"
+    endpoints = ["https://mirror.gcr.io"]
+  }
+}
+`, name)
+}
+
+func testAccKindClusterConfig_externalHost(name string) string {
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  api_server_cert_sans     = ["kind.example.com"]
+  api_server_external_host = "localhost"
+}
+`, name)
+}
+
+func testAccKindClusterConfig_dockerTLS(name, dockerHost, certPath string) string {
+	return fmt.Sprintf(`
+provider "kind" {
+  docker_host       = "%s"
+  docker_tls_verify = true
+  ca_material       = file("%s/ca.pem")
+  cert_material     = file("%s/cert.pem")
+  key_material      = file("%s/key.pem")
+}
+
+resource "kind_cluster" "test" {
+  name = "%s"
+
+  # Reachable when the daemon runs on another machine
+  kind_config {
+    networking {
+      api_server_address = "0.0.0.0"
+    }
+  }
+}
+`, dockerHost, certPath, certPath, certPath, name)
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestGenerateKindConfig tests the Kind configuration generation
@@ -104,7 +107,7 @@ func TestGenerateKindConfig(t *testing.T) {
 			d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, tt.input)
 			
 			// Generate the configuration
-			result := generateKindConfig(d)
+			result := testKindConfig(t, d)
 			
 			// Compare the results
 			assert.Equal(t, tt.expected, result)
@@ -192,7 +195,7 @@ func TestResourceSchema(t *testing.T) {
 	
 	// Test optional fields with defaults
 	assert.False(t, resource.Schema["node_image"].Required)
-	assert.Equal(t, "kindest/node:v1.28.0", resource.Schema["node_image"].Default)
+	assert.Equal(t, defaultNodeImage, resource.Schema["node_image"].Default)
 	
 	assert.False(t, resource.Schema["wait_for_ready"].Required)
 	assert.Equal(t, true, resource.Schema["wait_for_ready"].Default)
@@ -221,4 +224,159 @@ func TestResourceTimeouts(t *testing.T) {
 	// Default timeouts should be reasonable
 	assert.Equal(t, "10m0s", resource.Timeouts.Create.String())
 	assert.Equal(t, "5m0s", resource.Timeouts.Delete.String())
-}
\ No newline at end of file
+}
+
+// TestAllNodesReady tests parsing of kubectl get nodes output
+func TestAllNodesReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected bool
+	}{
+		{
+			name: "all nodes ready",
+			output: `NAME                 STATUS   ROLES           AGE   VERSION
+test-control-plane   Ready    control-plane   2m    v1.28.0
+test-worker          Ready    <none>          1m    v1.28.0`,
+			expected: true,
+		},
+		{
+			name: "worker not ready",
+			output: `NAME                 STATUS     ROLES           AGE   VERSION
+test-control-plane   Ready      control-plane   2m    v1.28.0
+test-worker          NotReady   <none>          1m    v1.28.0`,
+			expected: false,
+		},
+		{
+			name: "cordoned node ready",
+			output: `NAME                 STATUS                     ROLES           AGE   VERSION
+test-control-plane   Ready                      control-plane   2m    v1.28.0
+test-worker          Ready,SchedulingDisabled   <none>          1m    v1.28.0`,
+			expected: true,
+		},
+		{
+			name: "cordoned node not ready",
+			output: `NAME                 STATUS                        ROLES           AGE   VERSION
+test-control-plane   Ready                         control-plane   2m    v1.28.0
+test-worker          NotReady,SchedulingDisabled   <none>          1m    v1.28.0`,
+			expected: false,
+		},
+		{
+			name:     "no nodes",
+			output:   "",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, allNodesReady(tt.output))
+		})
+	}
+}
+
+// TestAllNodesRunning tests parsing of docker inspect output for node containers
+func TestAllNodesRunning(t *testing.T) {
+	assert.True(t, allNodesRunning("true\ntrue\n"))
+	assert.False(t, allNodesRunning("true\nfalse\n"))
+	assert.False(t, allNodesRunning("false\n"))
+	assert.False(t, allNodesRunning(""))
+}
+
+// TestRunningSchema tests that stopping a cluster is an in-place update
+func TestRunningSchema(t *testing.T) {
+	resource := resourceKindCluster()
+
+	assert.True(t, resource.Schema["running"].Optional)
+	assert.False(t, resource.Schema["running"].ForceNew)
+	assert.Equal(t, true, resource.Schema["running"].Default)
+	assert.Equal(t, "10m0s", resource.Timeouts.Update.String())
+}
+
+// TestKubernetesVersionSchema tests that kubernetes_version is exclusive with node_image
+func TestKubernetesVersionSchema(t *testing.T) {
+	resource := resourceKindCluster()
+
+	assert.True(t, resource.Schema["kubernetes_version"].ForceNew)
+	assert.Equal(t, []string{"node_image"}, resource.Schema["kubernetes_version"].ConflictsWith)
+	assert.True(t, resource.Schema["resolved_node_image"].Computed)
+
+	for _, v := range []string{"1.29", "1.29.2", "v1.28.0"} {
+		_, errs := resource.Schema["kubernetes_version"].ValidateFunc(v, "kubernetes_version")
+		assert.Empty(t, errs, v)
+	}
+	for _, v := range []string{"latest", "1", "1.29.2-rc.0"} {
+		_, errs := resource.Schema["kubernetes_version"].ValidateFunc(v, "kubernetes_version")
+		assert.NotEmpty(t, errs, v)
+	}
+}
+
+// TestValidateNodeImageDigest tests the plan-time check for digest pinned node images
+func TestValidateNodeImageDigest(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		expectError bool
+	}{
+		{
+			name: "tag allowed by default",
+			config: map[string]interface{}{
+				"name":       "test",
+				"node_image": "kindest/node:v1.28.0",
+			},
+		},
+		{
+			name: "tag rejected when digest required",
+			config: map[string]interface{}{
+				"name":                      "test",
+				"node_image":                "kindest/node:v1.28.0",
+				"require_node_image_digest": true,
+			},
+			expectError: true,
+		},
+		{
+			name: "digest accepted when required",
+			config: map[string]interface{}{
+				"name":                      "test",
+				"node_image":                "kindest/node:v1.28.0@sha256:b7a4cad12c197af3ba43202d3efe03246b3f0793f162afb40a33c923952d5b31",
+				"require_node_image_digest": true,
+			},
+		},
+		{
+			name: "kubernetes_version is always pinned",
+			config: map[string]interface{}{
+				"name":                      "test",
+				"kubernetes_version":        "1.28",
+				"require_node_image_digest": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testResourceDiff(t, resourceKindCluster(), tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// testKindConfig generates the Kind config of resource data, failing the test
+// on errors.
+func testKindConfig(t *testing.T, d *schema.ResourceData) map[string]interface{} {
+	t.Helper()
+	config, err := generateKindConfig(d)
+	require.NoError(t, err)
+	return config
+}
+
+// testResourceDiff plans the creation of a resource from raw configuration,
+// running its CustomizeDiff functions.
+func testResourceDiff(t *testing.T, r *schema.Resource, config map[string]interface{}) error {
+	t.Helper()
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), &ProviderConfig{})
+	return err
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKindManifest() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKindManifestCreate,
+		ReadContext:   resourceKindManifestRead,
+		UpdateContext: resourceKindManifestUpdate,
+		DeleteContext: resourceKindManifestDelete,
+
+		CustomizeDiff: planManifestObjects,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"cluster_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the Kind cluster to apply the manifest to",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Multi-document YAML manifest, applied with server-side apply",
+			},
+			"object_uids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "UIDs of the applied objects keyed by apiVersion/kind/namespace/name",
+			},
+		},
+	}
+}
+
+func resourceKindManifestCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	clusterName := d.Get("cluster_name").(string)
+
+	documents, err := parseManifest(d.Get("content").(string))
+	if err != nil {
+		return diag.Errorf("Failed to parse manifest: %s", err)
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	log.Printf("[INFO] Applying %d objects to Kind cluster %s", len(documents), clusterName)
+
+	errs := applyManifestObjects(kubeconfigPath, documents)
+	var applied []manifestDocument
+	var diags diag.Diagnostics
+	for i, document := range documents {
+		if errs[i] != nil {
+			diags = append(diags, applyErrorDiagnostic(document, errs[i]))
+			continue
+		}
+		applied = append(applied, document)
+	}
+	if len(applied) > 0 {
+		d.SetId(id.PrefixedUniqueId(clusterName + "-"))
+	}
+	if diags.HasError() {
+		// Track the objects that were applied, so they are deleted on destroy
+		if len(applied) > 0 {
+			uids, err := getManifestObjectUIDs(kubeconfigPath, applied)
+			if err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+			d.Set("object_uids", uids)
+		}
+		return diags
+	}
+
+	return resourceKindManifestRead(ctx, d, m)
+}
+
+func resourceKindManifestRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	clusterName := d.Get("cluster_name").(string)
+
+	if !clusterExists(config, clusterName) {
+		log.Printf("[WARN] Kind cluster %s not found, removing manifest %s from state", clusterName, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	running, err := clusterRunning(config, clusterName)
+	if err != nil {
+		return diag.Errorf("Failed to get Kind cluster state: %s", err)
+	}
+	if !running {
+		log.Printf("[INFO] Kind cluster %s is stopped, skipping manifest refresh", clusterName)
+		return nil
+	}
+
+	documents, err := parseManifest(d.Get("content").(string))
+	if err != nil {
+		return diag.Errorf("Failed to parse manifest: %s", err)
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	// Objects deleted or recreated outside of Terraform are left out, which
+	// plans applying them again
+	uids, err := getManifestObjectUIDs(kubeconfigPath, documents)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("object_uids", unchangedManifestObjectUIDs(d.Get("object_uids").(map[string]interface{}), uids))
+
+	return nil
+}
+
+func resourceKindManifestUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	clusterName := d.Get("cluster_name").(string)
+
+	oldContent, newContent := d.GetChange("content")
+	oldDocuments, err := parseManifest(oldContent.(string))
+	if err != nil {
+		return diag.Errorf("Failed to parse previous manifest: %s", err)
+	}
+	documents, err := parseManifest(newContent.(string))
+	if err != nil {
+		return diag.Errorf("Failed to parse manifest: %s", err)
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	if diags := applyManifestDocuments(kubeconfigPath, documents); diags.HasError() {
+		return diags
+	}
+
+	// Delete objects that were removed from the manifest
+	for _, document := range removedManifestDocuments(oldDocuments, documents) {
+		if err := deleteManifestDocument(kubeconfigPath, document); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceKindManifestRead(ctx, d, m)
+}
+
+func resourceKindManifestDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	clusterName := d.Get("cluster_name").(string)
+
+	if !clusterExists(config, clusterName) {
+		log.Printf("[WARN] Kind cluster %s not found, considering manifest %s deleted", clusterName, d.Id())
+		return nil
+	}
+
+	documents, err := parseManifest(d.Get("content").(string))
+	if err != nil {
+		return diag.Errorf("Failed to parse manifest: %s", err)
+	}
+
+	kubeconfigPath, cleanup, err := writeClusterKubeconfig(config, clusterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer cleanup()
+
+	// Delete in reverse order so namespaces and definitions go last
+	for i := len(documents) - 1; i >= 0; i-- {
+		if err := deleteManifestDocument(kubeconfigPath, documents[i]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// planManifestObjects validates the manifest and plans applying it again when
+// its content changed or applied objects went missing.
+func planManifestObjects(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("content") {
+		return d.SetNewComputed("object_uids")
+	}
+
+	documents, err := parseManifest(d.Get("content").(string))
+	if err != nil {
+		return fmt.Errorf("invalid manifest content: %s", err)
+	}
+	if len(documents) == 0 {
+		return fmt.Errorf("manifest content does not contain any objects")
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChange("content") {
+		return d.SetNewComputed("object_uids")
+	}
+
+	uids := d.Get("object_uids").(map[string]interface{})
+	for _, document := range documents {
+		if _, ok := uids[manifestObjectKey(document.Object)]; !ok {
+			log.Printf("[INFO] %s is missing from the cluster, planning to apply it again", document.Object)
+			return d.SetNewComputed("object_uids")
+		}
+	}
+	return nil
+}
+
+// getManifestObjectUIDs returns the UIDs of the live objects described by
+// documents, keyed by manifestObjectKey. Missing objects are left out.
+func getManifestObjectUIDs(kubeconfigPath string, documents []manifestDocument) (map[string]string, error) {
+	uids := map[string]string{}
+	for _, document := range documents {
+		uid, err := getManifestObjectUID(kubeconfigPath, document)
+		if err != nil {
+			return nil, err
+		}
+		if uid == "" {
+			log.Printf("[WARN] %s not found in the Kind cluster", document.Object)
+			continue
+		}
+		uids[manifestObjectKey(document.Object)] = uid
+	}
+	return uids, nil
+}
+
+// unchangedManifestObjectUIDs drops the live objects whose UID differs from
+// the one tracked in state, i.e. objects recreated outside of Terraform.
+// Objects without a tracked UID were just applied and are kept.
+func unchangedManifestObjectUIDs(tracked map[string]interface{}, live map[string]string) map[string]string {
+	uids := map[string]string{}
+	for key, uid := range live {
+		if previous, ok := tracked[key].(string); ok && previous != "" && previous != uid {
+			log.Printf("[WARN] %s was recreated outside of Terraform (UID %s, was %s)", key, uid, previous)
+			continue
+		}
+		uids[key] = uid
+	}
+	return uids
+}
+
+// removedManifestDocuments returns the documents of a previous manifest that
+// are no longer part of the current one.
+func removedManifestDocuments(previous, current []manifestDocument) []manifestDocument {
+	keep := map[string]bool{}
+	for _, document := range current {
+		keep[manifestObjectKey(document.Object)] = true
+	}
+
+	var removed []manifestDocument
+	for _, document := range previous {
+		if !keep[manifestObjectKey(document.Object)] {
+			removed = append(removed, document)
+		}
+	}
+	return removed
+}
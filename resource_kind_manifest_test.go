@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccKindManifest_basic tests applying, updating and pruning manifest objects
+func TestAccKindManifest_basic(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+	resourceName := "kind_manifest.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindManifestConfig_basic(rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "object_uids.%", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "object_uids.v1/Namespace//tf-acc"),
+					resource.TestCheckResourceAttrSet(resourceName, "object_uids.v1/ConfigMap/tf-acc/settings"),
+				),
+			},
+			// Objects removed from the manifest are deleted
+			{
+				Config: testAccKindManifestConfig_basic(rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "object_uids.%", "1"),
+					resource.TestCheckNoResourceAttr(resourceName, "object_uids.v1/ConfigMap/tf-acc/settings"),
+				),
+			},
+		},
+	})
+}
+
+func testAccKindManifestConfig_basic(name string, withConfigMap bool) string {
+	configMap := ""
+	if withConfigMap {
+		configMap = `
+    ---
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: settings
+      namespace: tf-acc
+    data:
+      key: value`
+	}
+
+	return fmt.Sprintf(`
+resource "kind_cluster" "test" {
+  name = "%s"
+}
+
+resource "kind_manifest" "test" {
+  cluster_name = kind_cluster.test.name
+  content      = <<-EOT
+    apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: tf-acc%s
+    EOT
+}
+`, name, configMap)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifestContent = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: apps
+`
+
+// TestPlanManifestObjects_validation tests plan-time validation of manifest content
+func TestPlanManifestObjects_validation(t *testing.T) {
+	err := testResourceDiff(t, resourceKindManifest(), map[string]interface{}{
+		"cluster_name": "test",
+		"content":      testManifestContent,
+	})
+	assert.NoError(t, err)
+
+	err = testResourceDiff(t, resourceKindManifest(), map[string]interface{}{
+		"cluster_name": "test",
+		"content":      "# nothing to apply\n",
+	})
+	assert.Error(t, err)
+
+	err = testResourceDiff(t, resourceKindManifest(), map[string]interface{}{
+		"cluster_name": "test",
+		"content":      "kind: ConfigMap\n",
+	})
+	assert.Error(t, err)
+}
+
+// TestPlanManifestObjects_drift tests that objects missing from the cluster are applied again
+func TestPlanManifestObjects_drift(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "test-1",
+		Attributes: map[string]string{
+			"id":                                     "test-1",
+			"cluster_name":                           "test",
+			"content":                                testManifestContent,
+			"object_uids.%":                          "2",
+			"object_uids.v1/Namespace//apps":         "uid-1",
+			"object_uids.v1/ConfigMap/apps/settings": "uid-2",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"cluster_name": "test",
+		"content":      testManifestContent,
+	})
+
+	diff, err := resourceKindManifest().Diff(context.Background(), state, config, &ProviderConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, diff, "no changes expected while all objects exist")
+
+	// The ConfigMap was deleted outside of Terraform
+	delete(state.Attributes, "object_uids.v1/ConfigMap/apps/settings")
+	state.Attributes["object_uids.%"] = "1"
+
+	diff, err = resourceKindManifest().Diff(context.Background(), state, config, &ProviderConfig{})
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.True(t, diff.Attributes["object_uids.%"].NewComputed)
+	assert.False(t, diff.RequiresNew())
+}
+
+// TestUnchangedManifestObjectUIDs tests that objects recreated outside of Terraform are dropped
+func TestUnchangedManifestObjectUIDs(t *testing.T) {
+	tracked := map[string]interface{}{
+		"v1/Namespace//apps":         "uid-1",
+		"v1/ConfigMap/apps/settings": "uid-2",
+	}
+	live := map[string]string{
+		"v1/Namespace//apps":         "uid-1",
+		"v1/ConfigMap/apps/settings": "uid-3",
+		"v1/Secret/apps/token":       "uid-4",
+	}
+
+	assert.Equal(t, map[string]string{
+		"v1/Namespace//apps":   "uid-1",
+		"v1/Secret/apps/token": "uid-4",
+	}, unchangedManifestObjectUIDs(tracked, live))
+
+	// Nothing is tracked right after applying
+	assert.Equal(t, live, unchangedManifestObjectUIDs(map[string]interface{}{}, live))
+}
+
+// TestRemovedManifestDocuments tests finding objects dropped from a manifest
+func TestRemovedManifestDocuments(t *testing.T) {
+	previous, err := parseManifest(testManifestContent)
+	require.NoError(t, err)
+	current, err := parseManifest("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n")
+	require.NoError(t, err)
+
+	removed := removedManifestDocuments(previous, current)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "v1/ConfigMap/apps/settings", manifestObjectKey(removed[0].Object))
+}
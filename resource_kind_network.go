@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceKindNetwork() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKindNetworkCreate,
+		ReadContext:   resourceKindNetworkRead,
+		DeleteContext: resourceKindNetworkDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the Docker network, referenced by the network attribute of kind_cluster",
+			},
+			"subnet": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "IPv4 subnet of the network, allocated by Docker when not set",
+			},
+			"gateway": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsIPv4Address,
+				Description:  "Gateway address of the subnet, allocated by Docker when not set",
+			},
+			"internal": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Restrict external access to the network",
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Labels of the Docker network",
+			},
+		},
+	}
+}
+
+func resourceKindNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	name := d.Get("name").(string)
+
+	log.Printf("[INFO] Creating Docker network: %s", name)
+
+	output, err := dockerCommand(config, networkCreateArgs(d)...).CombinedOutput()
+	if err != nil {
+		return diag.Errorf("Failed to create Docker network: %s\nOutput: %s", err, string(output))
+	}
+
+	d.SetId(strings.TrimSpace(string(output)))
+
+	log.Printf("[INFO] Docker network created successfully: %s", name)
+
+	return resourceKindNetworkRead(ctx, d, m)
+}
+
+// networkCreateArgs builds the docker network create arguments. Masquerading
+// is enabled like on the network kind creates itself, so nodes reach the
+// internet.
+func networkCreateArgs(d *schema.ResourceData) []string {
+	args := []string{"network", "create", "--driver", "bridge",
+		"--opt", "com.docker.network.bridge.enable_ip_masquerade=true"}
+
+	if subnet := d.Get("subnet").(string); subnet != "" {
+		args = append(args, "--subnet", subnet)
+	}
+	if gateway := d.Get("gateway").(string); gateway != "" {
+		args = append(args, "--gateway", gateway)
+	}
+	if d.Get("internal").(bool) {
+		args = append(args, "--internal")
+	}
+
+	labels := d.Get("labels").(map[string]interface{})
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, labels[key]))
+	}
+
+	return append(args, d.Get("name").(string))
+}
+
+// dockerNetwork is the subset of docker network inspect output the provider
+// reads.
+type dockerNetwork struct {
+	ID       string            `json:"Id"`
+	Name     string            `json:"Name"`
+	Internal bool              `json:"Internal"`
+	Labels   map[string]string `json:"Labels"`
+	IPAM     struct {
+		Config []struct {
+			Subnet  string `json:"Subnet"`
+			Gateway string `json:"Gateway"`
+		} `json:"Config"`
+	} `json:"IPAM"`
+}
+
+// parseDockerNetwork parses the JSON of a single network from docker network
+// inspect.
+func parseDockerNetwork(data []byte) (*dockerNetwork, error) {
+	var network dockerNetwork
+	if err := json.Unmarshal(data, &network); err != nil {
+		return nil, fmt.Errorf("failed to parse Docker network: %s", err)
+	}
+	return &network, nil
+}
+
+// ipv4Config returns the first IPv4 subnet and its gateway.
+func (n *dockerNetwork) ipv4Config() (string, string) {
+	for _, c := range n.IPAM.Config {
+		if !strings.Contains(c.Subnet, ":") {
+			return c.Subnet, c.Gateway
+		}
+	}
+	return "", ""
+}
+
+func resourceKindNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+
+	log.Printf("[INFO] Reading Docker network: %s", d.Id())
+
+	output, err := dockerCommand(config, "network", "inspect", "--format", "{{json .}}", d.Id()).CombinedOutput()
+	if err != nil {
+		if isNetworkNotFound(string(output)) {
+			log.Printf("[WARN] Docker network %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Failed to inspect Docker network: %s\nOutput: %s", err, string(output))
+	}
+
+	network, err := parseDockerNetwork(output)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	subnet, gateway := network.ipv4Config()
+	d.Set("name", network.Name)
+	d.Set("subnet", subnet)
+	d.Set("gateway", gateway)
+	d.Set("internal", network.Internal)
+	d.Set("labels", network.Labels)
+
+	return nil
+}
+
+func resourceKindNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+
+	log.Printf("[INFO] Deleting Docker network: %s", d.Id())
+
+	// Networks with attached containers fail to delete, which surfaces
+	// clusters that still use the network
+	output, err := dockerCommand(config, "network", "rm", d.Id()).CombinedOutput()
+	if err != nil {
+		if isNetworkNotFound(string(output)) {
+			log.Printf("[WARN] Docker network %s not found, considering it deleted", d.Id())
+			return nil
+		}
+		return diag.Errorf("Failed to delete Docker network: %s\nOutput: %s", err, string(output))
+	}
+
+	log.Printf("[INFO] Docker network deleted successfully: %s", d.Id())
+	return nil
+}
+
+// isNetworkNotFound reports whether docker CLI output says that a network does
+// not exist, e.g. "Error: No such network: kind".
+func isNetworkNotFound(output string) bool {
+	output = strings.ToLower(output)
+	return strings.Contains(output, "no such network") || strings.Contains(output, "not found")
+}
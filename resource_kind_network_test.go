@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccKindNetwork_sharedByClusters tests two clusters sharing a custom network
+func TestAccKindNetwork_sharedByClusters(t *testing.T) {
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckKindClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKindNetworkConfig_sharedByClusters(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("kind_network.test", "subnet", "172.30.0.0/24"),
+					resource.TestCheckResourceAttr("kind_network.test", "gateway", "172.30.0.1"),
+					resource.TestCheckResourceAttr("kind_cluster.a", "network", rName),
+					resource.TestCheckResourceAttr("kind_cluster.b", "network", rName),
+				),
+			},
+			{
+				ResourceName:      "kind_network.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccKindNetworkConfig_sharedByClusters(name string) string {
+	return fmt.Sprintf(`
+resource "kind_network" "test" {
+  name   = "%[1]s"
+  subnet = "172.30.0.0/24"
+}
+
+resource "kind_cluster" "a" {
+  name    = "%[1]s-a"
+  network = kind_network.test.name
+}
+
+resource "kind_cluster" "b" {
+  name    = "%[1]s-b"
+  network = kind_network.test.name
+}
+`, name)
+}
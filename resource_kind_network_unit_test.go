@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNetworkCreateArgs tests the docker network create arguments
+func TestNetworkCreateArgs(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindNetwork().Schema, map[string]interface{}{
+		"name":     "staging",
+		"subnet":   "172.30.0.0/16",
+		"gateway":  "172.30.0.1",
+		"internal": true,
+		"labels": map[string]interface{}{
+			"team": "platform",
+			"env":  "staging",
+		},
+	})
+
+	assert.Equal(t, []string{
+		"network", "create", "--driver", "bridge",
+		"--opt", "com.docker.network.bridge.enable_ip_masquerade=true",
+		"--subnet", "172.30.0.0/16",
+		"--gateway", "172.30.0.1",
+		"--internal",
+		"--label", "env=staging",
+		"--label", "team=platform",
+		"staging",
+	}, networkCreateArgs(d))
+}
+
+// TestNetworkCreateArgs_minimal tests that Docker allocates the subnet when none is configured
+func TestNetworkCreateArgs_minimal(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindNetwork().Schema, map[string]interface{}{
+		"name": "staging",
+	})
+
+	assert.Equal(t, []string{
+		"network", "create", "--driver", "bridge",
+		"--opt", "com.docker.network.bridge.enable_ip_masquerade=true",
+		"staging",
+	}, networkCreateArgs(d))
+}
+
+// TestParseDockerNetwork tests reading the IPv4 config of a dual-stack network
+func TestParseDockerNetwork(t *testing.T) {
+	network, err := parseDockerNetwork([]byte(`{
+		"Name": "kind",
+		"Id": "0123456789ab",
+		"Internal": false,
+		"Labels": {"env": "dev"},
+		"IPAM": {
+			"Config": [
+				{"Subnet": "fc00:f853:ccd:e793::/64", "Gateway": "fc00:f853:ccd:e793::1"},
+				{"Subnet": "172.18.0.0/16", "Gateway": "172.18.0.1"}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "kind", network.Name)
+	assert.Equal(t, map[string]string{"env": "dev"}, network.Labels)
+
+	subnet, gateway := network.ipv4Config()
+	assert.Equal(t, "172.18.0.0/16", subnet)
+	assert.Equal(t, "172.18.0.1", gateway)
+}
+
+// TestParseDockerNetwork_invalid tests that malformed inspect output is reported
+func TestParseDockerNetwork_invalid(t *testing.T) {
+	_, err := parseDockerNetwork([]byte("Error: No such network"))
+	assert.Error(t, err)
+}
+
+// TestClusterNetwork tests falling back to the kind network
+func TestClusterNetwork(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name": "test",
+	})
+	assert.Equal(t, "kind", clusterNetwork(d))
+
+	d = schema.TestResourceDataRaw(t, resourceKindCluster().Schema, map[string]interface{}{
+		"name":    "test",
+		"network": "staging",
+	})
+	assert.Equal(t, "staging", clusterNetwork(d))
+}
+
+// TestIsNetworkNotFound tests recognizing missing networks in docker CLI output
+func TestIsNetworkNotFound(t *testing.T) {
+	assert.True(t, isNetworkNotFound("Error: No such network: 3f2a9c1d"))
+	assert.True(t, isNetworkNotFound("Error response from daemon: network staging not found"))
+	assert.False(t, isNetworkNotFound("Error response from daemon: network staging has active endpoints"))
+}
+
+// TestWithEnv tests that variables are added on top of the command environment
+func TestWithEnv(t *testing.T) {
+	cmd := withEnv(exec.Command("kind"), "KIND_EXPERIMENTAL_DOCKER_NETWORK=staging")
+	assert.Contains(t, cmd.Env, "KIND_EXPERIMENTAL_DOCKER_NETWORK=staging")
+	assert.Greater(t, len(cmd.Env), 1)
+
+	cmd = withEnv(kindCommand(&ProviderConfig{DockerHost: "tcp://docker:2375"}), "KIND_EXPERIMENTAL_DOCKER_NETWORK=staging")
+	assert.Contains(t, cmd.Env, "DOCKER_HOST=tcp://docker:2375")
+	assert.Contains(t, cmd.Env, "KIND_EXPERIMENTAL_DOCKER_NETWORK=staging")
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKindNodeImageBuild() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKindNodeImageBuildCreate,
+		ReadContext:   resourceKindNodeImageBuildRead,
+		UpdateContext: resourceKindNodeImageBuildUpdate,
+		DeleteContext: resourceKindNodeImageBuildDelete,
+
+		CustomizeDiff: setNodeImageSourceHash,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"source_path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Path to a Kubernetes source tree or release tarball",
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the node image to build, e.g. kindest/node:custom",
+			},
+			"base_image": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Base image to build the node image from. Defaults to the base image of the kind CLI",
+			},
+			"keep_image": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Keep the image in the local image cache when the resource is destroyed",
+			},
+			"source_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Content hash of the build inputs, the image is rebuilt when it changes",
+			},
+			"image_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the built image",
+			},
+		},
+	}
+}
+
+func resourceKindNodeImageBuildCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	sourcePath := d.Get("source_path").(string)
+	image := d.Get("image").(string)
+
+	log.Printf("[INFO] Building Kind node image %s from %s", image, sourcePath)
+
+	sourceHash, err := hashNodeImageSource(sourcePath, d.Get("base_image").(string))
+	if err != nil {
+		return diag.Errorf("Failed to hash node image sources: %s", err)
+	}
+	if err := checkPlannedSourceHash(d.Get("source_hash").(string), sourceHash); err != nil {
+		return diag.FromErr(err)
+	}
+
+	args := []string{"build", "node-image", sourcePath, "--image", image}
+	if baseImage, ok := d.GetOk("base_image"); ok {
+		args = append(args, "--base-image", baseImage.(string))
+	}
+
+	cmd := kindCommand(config, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return diag.Errorf("Failed to build Kind node image: %s\nOutput: %s", err, string(output))
+	}
+
+	log.Printf("[INFO] Kind node image built successfully: %s", image)
+
+	d.SetId(image)
+	d.Set("source_hash", sourceHash)
+
+	return resourceKindNodeImageBuildRead(ctx, d, m)
+}
+
+func resourceKindNodeImageBuildRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	image := d.Id()
+
+	output, err := dockerCommand(config, "image", "inspect", "--format", "{{.Id}}", image).Output()
+	if err != nil {
+		log.Printf("[WARN] Kind node image %s not found, removing from state", image)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("image_id", strings.TrimSpace(string(output)))
+	return nil
+}
+
+func resourceKindNodeImageBuildUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Only keep_image can change in place and it is used on delete
+	return resourceKindNodeImageBuildRead(ctx, d, m)
+}
+
+func resourceKindNodeImageBuildDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	config := m.(*ProviderConfig)
+	image := d.Id()
+
+	if d.Get("keep_image").(bool) {
+		log.Printf("[INFO] Keeping Kind node image %s", image)
+		return nil
+	}
+
+	log.Printf("[INFO] Removing Kind node image: %s", image)
+
+	output, err := dockerCommand(config, "image", "rm", image).CombinedOutput()
+	if err != nil {
+		if strings.Contains(strings.ToLower(string(output)), "no such image") {
+			return nil
+		}
+		return diag.Errorf("Failed to remove Kind node image: %s\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// setNodeImageSourceHash plans a rebuild when the build inputs changed since
+// the image was built.
+func setNodeImageSourceHash(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("source_path") || !d.NewValueKnown("base_image") {
+		return d.SetNewComputed("source_hash")
+	}
+
+	sourceHash, err := hashNodeImageSource(d.Get("source_path").(string), d.Get("base_image").(string))
+	if err != nil {
+		return fmt.Errorf("failed to hash node image sources: %s", err)
+	}
+	if d.Get("source_hash").(string) == sourceHash {
+		return nil
+	}
+	return d.SetNew("source_hash", sourceHash)
+}
+
+// checkPlannedSourceHash fails when the build inputs changed between plan and
+// apply, since the built image would not match the planned source_hash. The
+// planned hash is empty when the source was not known at plan time.
+func checkPlannedSourceHash(planned, actual string) error {
+	if planned == "" || planned == actual {
+		return nil
+	}
+	return fmt.Errorf("node image sources changed after the plan was made (planned source_hash %s, now %s), plan again", planned, actual)
+}
+
+// nodeImageSourceSkipDirs are not part of the build inputs of a Kubernetes
+// source tree.
+var nodeImageSourceSkipDirs = map[string]bool{
+	".git":    true,
+	"_output": true,
+}
+
+// hashNodeImageSource hashes the content of the inputs of a node image build:
+// the tarball, or the path and content of every file of a source tree.
+func hashNodeImageSource(sourcePath, baseImage string) (string, error) {
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "base_image=%s\n", baseImage)
+
+	if !info.IsDir() {
+		if err := hashFile(h, sourcePath); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	err = filepath.WalkDir(sourcePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && nodeImageSourceSkipDirs[entry.Name()] {
+			return filepath.SkipDir
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(sourcePath, path)
+		if err != nil {
+			return err
+		}
+		// The size delimits the content of each file
+		fmt.Fprintf(h, "%s %d\n", filepath.ToSlash(rel), info.Size())
+		return hashFile(h, path)
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFile writes the content of a file to h.
+func hashFile(h io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	return err
+}
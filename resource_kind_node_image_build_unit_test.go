@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHashNodeImageSource_directory tests that source tree content changes change the hash
+func TestHashNodeImageSource_directory(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "cmd", "kubelet", "kubelet.go"), "package main")
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module k8s.io/kubernetes")
+
+	initial, err := hashNodeImageSource(dir, "")
+	require.NoError(t, err)
+
+	again, err := hashNodeImageSource(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, initial, again)
+
+	// Build output and git metadata are not inputs
+	writeTestFile(t, filepath.Join(dir, "_output", "bin", "kubelet"), "binary")
+	writeTestFile(t, filepath.Join(dir, ".git", "HEAD"), "ref: refs/heads/master")
+	unchanged, err := hashNodeImageSource(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, initial, unchanged)
+
+	withBase, err := hashNodeImageSource(dir, "kindest/base:v20230606")
+	require.NoError(t, err)
+	assert.NotEqual(t, initial, withBase)
+
+	// Touching files without changing them keeps the hash
+	path := filepath.Join(dir, "cmd", "kubelet", "kubelet.go")
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	touched, err := hashNodeImageSource(dir, "")
+	require.NoError(t, err)
+	assert.Equal(t, initial, touched)
+
+	// Edits are detected even when size and modification time are restored
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	writeTestFile(t, path, "package kube")
+	require.NoError(t, os.Chtimes(path, info.ModTime(), info.ModTime()))
+	changed, err := hashNodeImageSource(dir, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, initial, changed)
+}
+
+// TestHashNodeImageSource_tarball tests that release tarballs are hashed by content
+func TestHashNodeImageSource_tarball(t *testing.T) {
+	tarball := filepath.Join(t.TempDir(), "kubernetes-server-linux-amd64.tar.gz")
+	writeTestFile(t, tarball, "release v1")
+
+	initial, err := hashNodeImageSource(tarball, "")
+	require.NoError(t, err)
+
+	writeTestFile(t, tarball, "release v2")
+	changed, err := hashNodeImageSource(tarball, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, initial, changed)
+}
+
+// TestHashNodeImageSource_missing tests the error for missing sources
+func TestHashNodeImageSource_missing(t *testing.T) {
+	_, err := hashNodeImageSource(filepath.Join(t.TempDir(), "missing"), "")
+	assert.Error(t, err)
+}
+
+// TestCheckPlannedSourceHash tests detecting sources changed between plan and apply
+func TestCheckPlannedSourceHash(t *testing.T) {
+	assert.NoError(t, checkPlannedSourceHash("", "abcd"))
+	assert.NoError(t, checkPlannedSourceHash("abcd", "abcd"))
+	assert.ErrorContains(t, checkPlannedSourceHash("abcd", "ef01"), "changed after the plan was made")
+}
+
+// TestNodeImageBuildResourceSchema tests that input changes rebuild the image
+func TestNodeImageBuildResourceSchema(t *testing.T) {
+	resource := resourceKindNodeImageBuild()
+
+	for _, key := range []string{"source_path", "image", "base_image", "source_hash"} {
+		assert.True(t, resource.Schema[key].ForceNew, key)
+	}
+	assert.False(t, resource.Schema["keep_image"].ForceNew)
+	assert.True(t, resource.Schema["image_id"].Computed)
+}
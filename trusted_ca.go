@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// trustedCAPath is the directory the trusted CA certificates are mounted to
+// on the nodes. update-ca-certificates picks up certificates in
+// subdirectories of /usr/local/share/ca-certificates.
+const trustedCAPath = "/usr/local/share/ca-certificates/terraform-provider-kind"
+
+// validateCACertificate checks that a value is a PEM encoded certificate.
+func validateCACertificate(v interface{}, k string) ([]string, []error) {
+	block, _ := pem.Decode([]byte(v.(string)))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, []error{fmt.Errorf("%s must be a PEM encoded CERTIFICATE", k)}
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid certificate: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// allNodes returns all nodes of a generated Kind config, adding a default
+// control-plane node when no nodes are configured.
+func allNodes(config map[string]interface{}) []map[string]interface{} {
+	firstControlPlaneNode(config)
+	return config["nodes"].([]map[string]interface{})
+}
+
+// prepareTrustedCAs writes the trusted CA certificates to the host before the
+// nodes mount them.
+func prepareTrustedCAs(d *schema.ResourceData) error {
+	certificates := expandStringList(d.Get("trusted_ca_certificates").([]interface{}))
+	for i, certificate := range certificates {
+		name := filepath.Join("ca-certificates", fmt.Sprintf("ca-%d.crt", i))
+		if _, err := writeClusterFile(d.Get("name").(string), name, certificate, 0644); err != nil {
+			return fmt.Errorf("failed to write CA certificate %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// configureTrustedCAs mounts the trusted CA certificates into every node.
+func configureTrustedCAs(d *schema.ResourceData, config map[string]interface{}) error {
+	if len(d.Get("trusted_ca_certificates").([]interface{})) == 0 {
+		return nil
+	}
+
+	dataDir, err := clusterDataDir(d.Get("name").(string))
+	if err != nil {
+		return err
+	}
+	for _, node := range allNodes(config) {
+		appendNodeExtraMount(node, filepath.Join(dataDir, "ca-certificates"), trustedCAPath, true)
+	}
+	return nil
+}
+
+// activateTrustedCAs adds the mounted certificates to the system trust store
+// of every node and restarts containerd, which reads the store on start.
+func activateTrustedCAs(d *schema.ResourceData, config *ProviderConfig, clusterName string) error {
+	if len(d.Get("trusted_ca_certificates").([]interface{})) == 0 {
+		return nil
+	}
+
+	log.Printf("[INFO] Activating trusted CA certificates on Kind cluster %s", clusterName)
+
+	if err := execOnClusterNodes(config, clusterName, "update-ca-certificates"); err != nil {
+		return err
+	}
+	return execOnClusterNodes(config, clusterName, "systemctl", "restart", "containerd")
+}